@@ -5,20 +5,25 @@ import (
 	"dayz-launcher-go/internal/a2s"
 	"dayz-launcher-go/internal/dayz"
 	"dayz-launcher-go/internal/discord"
+	"dayz-launcher-go/internal/opener"
+	"dayz-launcher-go/internal/profiles"
 	"syscall"
 
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamlocate"
 	"dayz-launcher-go/internal/steamworks"
+	"dayz-launcher-go/internal/workshop"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -26,21 +31,102 @@ import (
 
 const UseNativeSteamworks = true
 
+// Workshop details older than this are refetched before an outdated check
+const outdatedCheckMaxAge = 5 * time.Minute
+
 // App struct
 type App struct {
-	ctx               context.Context
-	httpClient        *http.Client
-	lastPersonaName   string
-	priorityCooldowns map[string]time.Time
+	ctx        context.Context
+	onEmit     func(event string, data interface{}) // Sees every frontend event, for tests
+	steam      steamworks.SteamClient
+	httpClient *http.Client
+	downloads  *downloadManager
+	dlStats    *downloadStats
+
+	// Written from the Steam callback loop as well as bindings
+	personaMu       sync.Mutex
+	lastPersonaName string
+
+	// Workshop details persisted across sessions (titles, sizes, update times)
+	modCache       *workshop.Cache
+	modInfoMu      sync.Mutex
+	modInfoPending map[string]bool
+
+	joinMu sync.Mutex
+	join   *joinJob
+
+	// Registered local mod folders (local_mods.json), and the mod lists of
+	// verified servers by ip:gamePort for matching them at launch
+	localModsMu  sync.Mutex
+	serverModsMu sync.Mutex
+	serverMods   map[string][]dayz.Mod
+
+	// On-disk view of installed mods, used while Steam is closed
+	invMu     sync.Mutex
+	inventory *workshop.Inventory
+
+	// Download polling is throttled when Steam delivers callbacks
+	pollMu           sync.Mutex
+	downloadsActive  bool
+	lastDownloadScan time.Time
+
+	// A2S info for servers friends are playing on, by ip:gamePort
+	friendSrvMu   sync.Mutex
+	friendServers map[string]*friendServer
+
+	// Rendered friend avatars
+	avatarMu sync.Mutex
+	avatars  map[avatarKey]avatarEntry
+
+	// Server the running game is on, and an invite waiting to be accepted
+	sessionMu     sync.Mutex
+	session       *ServerInvite
+	inviteMu      sync.Mutex
+	pendingInvite *ServerInvite
+
+	// Settings of the logged in Steam account
+	profileMu sync.Mutex
+	profiles  *profiles.Store
+	profile   *profiles.Profile
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{
+	return newApp(steamworks.Native())
+}
+
+// newApp builds the App on top of any Steam backend, e.g. steamfake
+func newApp(steam steamworks.SteamClient) *App {
+	a := &App{
+		steam: steam,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		priorityCooldowns: make(map[string]time.Time),
+	}
+	a.downloads = newDownloadManager(steam, a.emit)
+	steam.SetCallbackHandler(a.handleSteamCallback)
+	a.dlStats = newDownloadStats()
+	a.modInfoPending = make(map[string]bool)
+	a.friendServers = make(map[string]*friendServer)
+	a.avatars = make(map[avatarKey]avatarEntry)
+	a.profiles = newProfileStore()
+
+	cachePath := ""
+	if dir := ensureConfigDir(); dir != "" {
+		cachePath = filepath.Join(dir, "workshop_cache.json")
+	}
+	a.modCache = workshop.NewCache(cachePath, a.httpClient, workshop.DefaultTTL)
+	a.modCache.SetFetcher(a.fetchModDetails)
+	return a
+}
+
+// emit sends an event to the frontend once the runtime context exists
+func (a *App) emit(event string, data interface{}) {
+	if a.onEmit != nil {
+		a.onEmit(event, data)
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, event, data)
 	}
 }
 
@@ -49,6 +135,12 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
+	// Started from a deep link or a Steam join
+	if inv, ok := parseInviteArgs(os.Args[1:]); ok {
+		a.receiveInvite(inv)
+	}
+	go registerDeepLinks()
+
 	if UseNativeSteamworks {
 		// Initialize Native Steamworks
 		// Ticker loop for callbacks
@@ -61,9 +153,10 @@ func (a *App) startup(ctx context.Context) {
 				discord.UpdatePresence("Browsing Servers", "In Launcher", "logo", "DayZ Launcher")
 			}
 
-			if err := steamworks.Init(); err != nil {
+			if err := a.steam.Init(); err != nil {
 				fmt.Println("[App] Native Steamworks Init Failed:", err)
 			}
+			a.syncProfile()
 
 			// Ticker for callbacks (Reduced from 33ms to 500ms for lower CPU)
 			ticker := time.NewTicker(500 * time.Millisecond)
@@ -82,27 +175,35 @@ func (a *App) startup(ctx context.Context) {
 					discord.Close()
 					return
 				case <-ticker.C:
-					if !steamworks.IsInitialized() {
+					if !a.steam.IsInitialized() {
 						// Attempt to initialize if not already connected
-						if err := steamworks.Init(); err != nil {
+						if err := a.steam.Init(); err != nil {
 							// Still failed, just ignore until next tick
 						} else {
 							fmt.Println("[App] Native Steamworks Initialized via Loop")
 						}
 					} else {
 						// Only run callbacks if initialized
-						steamworks.RunCallbacks()
+						a.steam.RunCallbacks()
 					}
-				case <-dlTicker.C:
-					// Poll downloads and emit event if active
-					if steamworks.IsInitialized() {
+				case now := <-dlTicker.C:
+					// Poll downloads and emit event if active. Installs and
+					// subscriptions arrive as callbacks, so an idle launcher
+					// mostly skips this.
+					if a.steam.IsInitialized() && a.shouldPollDownloads(now) {
+						// Managed queue: stall detection, priority re-issues, status events
+						a.downloads.Poll()
+
+						active := false
 						data := a.GetActiveDownloads() // Reusing the method which formats correctly
 						if mapData, ok := data.(map[string]interface{}); ok {
 							if list, ok := mapData["data"].([]map[string]interface{}); ok && len(list) > 0 {
+								active = true
 								// Emit 'download-update' event to frontend
 								runtime.EventsEmit(ctx, "download-update", data)
 							}
 						}
+						a.setDownloadsActive(active)
 					}
 				case <-discordTicker.C:
 					// Check if Discord process is running (Force disconnect if not)
@@ -186,11 +287,9 @@ func (a *App) BattleMetricsFetch(url string) BMResponse {
 // -- UDP METHODS --
 
 func (a *App) FetchServerInfo(ip string, port int, timeoutMs int) (map[string]interface{}, error) {
-	// Default to 2s if 0 or negative
-	if timeoutMs <= 0 {
-		timeoutMs = 2000
-	}
-	info, err := a2s.QueryInfo(ip, port, time.Duration(timeoutMs)*time.Millisecond)
+	// timeoutMs never took effect (a2s always waited 3s); the default keeps
+	// that for existing callers
+	info, err := a2s.QueryInfo(ip, port, 0)
 	if err != nil {
 		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
@@ -220,11 +319,8 @@ func (a *App) FetchServerRules(ip string, port int) (map[string]interface{}, err
 }
 
 func (a *App) FetchServerPlayers(ip string, port int, timeoutMs int) (map[string]interface{}, error) {
-	// Default to 2s
-	if timeoutMs <= 0 {
-		timeoutMs = 2000
-	}
-	players, err := a2s.QueryPlayers(ip, port, time.Duration(timeoutMs)*time.Millisecond)
+	// Same fixed timeout as FetchServerInfo
+	players, err := a2s.QueryPlayers(ip, port, 0)
 	if err != nil {
 		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
@@ -241,9 +337,9 @@ func (a *App) FetchServerPlayers(ip string, port int, timeoutMs int) (map[string
 
 func (a *App) LoginSteam() (interface{}, error) {
 	// 1. Try Init if not initialized
-	if !steamworks.IsInitialized() {
+	if !a.steam.IsInitialized() {
 		fmt.Println("[App] LoginSteam: Steam not initialized, attempting init...")
-		if err := steamworks.Init(); err != nil {
+		if err := a.steam.Init(); err != nil {
 			fmt.Printf("[App] LoginSteam: Init failed: %v\n", err)
 			return map[string]interface{}{"success": false, "error": "Could not connect to Steam"}, nil
 		}
@@ -252,13 +348,14 @@ func (a *App) LoginSteam() (interface{}, error) {
 	}
 
 	// 2. Fetch Name
-	name := steamworks.GetPersonaName()
+	name := a.steam.GetPersonaName()
 	if name == "" {
 		return map[string]interface{}{"success": false}, nil
 	}
 
 	// 3. Update Cache
-	a.lastPersonaName = name
+	a.rememberPersonaName(name)
+	a.syncProfile()
 
 	// Emit Event so Frontend updates immediately (if called from other modals)
 	runtime.EventsEmit(a.ctx, "steam-connected", map[string]interface{}{"connected": true, "name": name})
@@ -268,18 +365,16 @@ func (a *App) LoginSteam() (interface{}, error) {
 
 func (a *App) GetSteamStatus() interface{} {
 	// Check if Steamworks is initialized
-	isInit := steamworks.IsInitialized()
+	isInit := a.steam.IsInitialized()
 	if !isInit {
 		return map[string]interface{}{"success": false, "connected": false, "error": "Steam not running"}
 	}
 
-	name := steamworks.GetPersonaName()
-	if name != "" {
-		a.lastPersonaName = name
-	}
+	a.rememberPersonaName(a.steam.GetPersonaName())
+	a.syncProfile()
 
 	// Fallback to cached name to prevent flicker
-	finalName := a.lastPersonaName
+	finalName := a.cachedPersonaName()
 	if finalName == "" {
 		finalName = "Survivor" // Default until fetched
 	}
@@ -287,23 +382,41 @@ func (a *App) GetSteamStatus() interface{} {
 	return map[string]interface{}{"success": true, "connected": true, "name": finalName}
 }
 
+// rememberPersonaName caches the Steam name; empty names are ignored
+func (a *App) rememberPersonaName(name string) {
+	if name == "" {
+		return
+	}
+	a.personaMu.Lock()
+	a.lastPersonaName = name
+	a.personaMu.Unlock()
+}
+
+// cachedPersonaName is the last Steam name seen, or ""
+func (a *App) cachedPersonaName() string {
+	a.personaMu.Lock()
+	defer a.personaMu.Unlock()
+	return a.lastPersonaName
+}
+
 func (a *App) SubscribeWorkshop(modId string) (interface{}, error) {
-	err := steamworks.SubscribeMod(modId)
+	err := a.steam.SubscribeMod(modId)
 	if err != nil {
 		fmt.Printf("[App] SubscribeWorkshop Failed: %v\n", err)
 		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
+	a.markDownloadsActive()
 	return map[string]interface{}{"success": true}, nil
 }
 
 func (a *App) PrioritizeWorkshop(modId string) (interface{}, error) {
-	// Force Download Priority
-	steamworks.DownloadItem(modId, true)
-	return map[string]interface{}{"success": true}, nil
+	// Force Download Priority (rate limited per mod by the download manager)
+	issued := a.downloads.Prioritize(modId)
+	return map[string]interface{}{"success": true, "issued": issued}, nil
 }
 
 func (a *App) UnsubscribeWorkshop(modId string) (interface{}, error) {
-	err := steamworks.UnsubscribeMod(modId)
+	err := a.steam.UnsubscribeMod(modId)
 	if err != nil {
 		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
@@ -315,11 +428,11 @@ func (a *App) UnsubscribeAll() (interface{}, error) {
 		fmt.Println("[App] Unsubscribing all mods (ACTIVE DOWNLOADS WILL BE STOPPED)...")
 
 		// 1. API Unsubscribe (Steamworks)
-		items := steamworks.GetSubscribedItems()
+		items := a.steam.GetSubscribedItems()
 		count := 0
 		errors := 0
 		for _, id := range items {
-			if err := steamworks.UnsubscribeMod(fmt.Sprintf("%d", id)); err == nil {
+			if err := a.steam.UnsubscribeMod(fmt.Sprintf("%d", id)); err == nil {
 				count++
 			} else {
 				errors++
@@ -334,7 +447,7 @@ func (a *App) UnsubscribeAll() (interface{}, error) {
 
 func (a *App) DeleteAllModFiles() (interface{}, error) {
 	fmt.Println("[App] Nuke Mode: Deleting all mod files from filesystem...")
-	workshopPath := getWorkshopPath()
+	workshopPath := a.getWorkshopPath()
 	count := 0
 	errors := 0
 
@@ -431,11 +544,19 @@ func (a *App) DeleteAllModFiles() (interface{}, error) {
 }
 
 func (a *App) GetFriendsList() []steamworks.SteamFriend {
-	return steamworks.GetFriends()
+	return a.steam.GetFriends()
 }
 
 func (a *App) GetSubscribedMods() []string {
-	ids := steamworks.GetSubscribedItems()
+	if !a.steam.IsInitialized() {
+		// Steam is closed: report what's installed on disk instead
+		if inv := a.offlineInventory(); inv != nil {
+			return inv.InstalledIDs()
+		}
+		return nil
+	}
+
+	ids := a.steam.GetSubscribedItems()
 	var result []string
 	for _, id := range ids {
 		result = append(result, fmt.Sprintf("%d", id))
@@ -443,66 +564,62 @@ func (a *App) GetSubscribedMods() []string {
 	return result
 }
 
+// OpenChat takes the SteamID as a string (any format steamid.Parse knows),
+// since JS numbers can't hold 64 bits
 func (a *App) OpenChat(steamIdString string) (interface{}, error) {
-	// Frontend sends string because JS Numbers lose precision for uint64
-	// Convert to uint64
-	var steamID uint64
-	n, _ := fmt.Sscanf(steamIdString, "%d", &steamID)
-
-	fmt.Printf("[App] OpenChat Requested for: %s (Parsed: %d)\n", steamIdString, steamID)
-
-	if n > 0 && steamID != 0 {
-		steamworks.OpenChat(steamID)
-
-		// Fallback: Also try opening via Protocol Handler just in case Overlay isn't hooked
-		// This ensures it opens a window even if the overlay toggle fails
-		go func() {
-			time.Sleep(100 * time.Millisecond)
-			exec.Command("explorer", fmt.Sprintf("steam://friends/message/%d", steamID)).Start()
-		}()
+	steamID, err := steamid.Parse(steamIdString)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	fmt.Printf("[App] OpenChat Requested for: %s\n", steamID)
 
-		return map[string]interface{}{"success": true}, nil
+	// Use the overlay when it's hooked into the launcher, otherwise the
+	// steam:// handler opens the Steam client's chat window
+	if a.steam.OpenChat(steamID.Uint64()) {
+		return map[string]interface{}{"success": true, "method": "overlay"}, nil
+	}
+	if err := opener.Open("steam://friends/message/" + steamID.String()); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
-	return map[string]interface{}{"success": false, "error": "Invalid SteamID"}, nil
+	return map[string]interface{}{"success": true, "method": "protocol"}, nil
 }
 
 // getWorkshopPath tries to find the DayZ workshop content directory
-func getWorkshopPath() string {
-	// 1. Try Registry (SteamPath)
-	// We execute "reg query" similar to nodejs sidecar, but pure Go has x/sys/windows/registry.
-	// For simplicity and avoiding external deps, let's use the CLI method or just common paths first.
-	// Actually, Wails app might be running in a context where we can just check common paths easily.
-
-	commonPaths := []string{
-		`C:\Program Files (x86)\Steam\steamapps\workshop\content\221100`,
-		`C:\Program Files\Steam\steamapps\workshop\content\221100`,
-		`D:\SteamLibrary\steamapps\workshop\content\221100`,
-		`E:\SteamLibrary\steamapps\workshop\content\221100`,
-		`F:\SteamLibrary\steamapps\workshop\content\221100`,
-		`C:\Steam\steamapps\workshop\content\221100`,
-	}
-
-	for _, p := range commonPaths {
-		if _, err := os.Stat(p); err == nil {
-			return p
+func (a *App) getWorkshopPath() string {
+	// Steam knows best: derive it from the folder of any installed mod
+	// (.../steamapps/workshop/content/221100/<id>)
+	for _, id := range a.steam.GetSubscribedItems() {
+		if p := a.steam.ResolveModPath(fmt.Sprintf("%d", id)); p != "" {
+			return filepath.Dir(p)
 		}
 	}
 
-	// 2. Try to derive from executable path if possible?
-	// (Not reliable if launcher is standalone)
-
-	return ""
+	// Otherwise find the library holding DayZ via libraryfolders.vdf
+	steam, err := steamlocate.Locate()
+	if err != nil {
+		return ""
+	}
+	return steam.WorkshopContentDir(dayz.AppID)
 }
 
 func (a *App) GetActiveDownloads() interface{} {
+	if UseNativeSteamworks && !a.steam.IsInitialized() {
+		return a.offlineDownloads()
+	}
 	if UseNativeSteamworks {
 		// Poll subscribed items for download status
-		items := steamworks.GetSubscribedItems()
+		items := a.steam.GetSubscribedItems()
 		var activeDownloads []map[string]interface{}
 
+		now := time.Now()
+		active := make(map[string]bool)
+		var unnamed []string
+		var totalSpeed float64
+		var remaining uint64
+
 		for _, id := range items {
 			sid := fmt.Sprintf("%d", id)
-			state := steamworks.GetItemState(sid)
+			state := a.steam.GetItemState(sid)
 			// Flags: 4=Installed, 8=NeedsUpdate, 16=Downloading, 32=Downloaded
 			// We check for Downloading (16) or NeedsUpdate(8)
 
@@ -511,7 +628,7 @@ func (a *App) GetActiveDownloads() interface{} {
 
 			if isDownloading || isQueued {
 
-				current, total := steamworks.GetDownloadInfo(sid)
+				current, total := a.steam.GetDownloadInfo(sid)
 				var progress float64
 				if total > 0 {
 					progress = (float64(current) / float64(total)) * 100
@@ -522,33 +639,66 @@ func (a *App) GetActiveDownloads() interface{} {
 					status = "downloading"
 				}
 
+				// Smoothed speed from the sliding window
+				active[sid] = true
+				a.dlStats.Observe(sid, current, now)
+				speed := a.dlStats.Speed(sid)
+				totalSpeed += speed
+
+				var left uint64
+				if total > current {
+					left = total - current
+					remaining += left
+				}
+
+				name, ok := a.cachedModTitle(sid)
+				if !ok {
+					name = fmt.Sprintf("Mod %s", sid)
+					unnamed = append(unnamed, sid)
+				}
+
 				activeDownloads = append(activeDownloads, map[string]interface{}{
 					"id":         sid,
-					"name":       fmt.Sprintf("Mod %s", sid),
+					"name":       name,
 					"status":     status,
 					"progress":   progress,
 					"current":    current,
 					"total":      total,
 					"stateFlags": state,
+					"speed":      speed,
+					"eta":        etaSeconds(left, speed),
 				})
 			}
 		}
 
-		name := steamworks.GetPersonaName()
-		if len(activeDownloads) > 0 {
+		a.dlStats.Prune(active)
+		if len(unnamed) > 0 {
+			a.prefetchModInfo(unnamed)
 		}
+
+		name := a.steam.GetPersonaName()
 		// Emulate Sidecar Event Structure
 		return map[string]interface{}{
 			"type": "download-update",
 			"data": activeDownloads,
-			"meta": map[string]interface{}{"connected": true, "name": name},
+			"meta": map[string]interface{}{
+				"connected": true,
+				"name":      name,
+				"speed":     totalSpeed,
+				"eta":       etaSeconds(remaining, totalSpeed),
+				"join":      a.joinProgress(),
+			},
 		}
 	}
 	return nil
 }
 
 func (a *App) CheckMod(modId string, verify bool) (interface{}, error) {
-	state := steamworks.GetItemState(modId)
+	if !a.steam.IsInitialized() {
+		return a.offlineModStatus(modId, verify), nil
+	}
+
+	state := a.steam.GetItemState(modId)
 	status := "unknown"
 
 	if (state & 16) != 0 {
@@ -558,9 +708,13 @@ func (a *App) CheckMod(modId string, verify bool) (interface{}, error) {
 	} else if (state & 4) != 0 {
 		// Installed
 		// Verification
-		path := steamworks.ResolveModPath(modId)
+		path := a.steam.ResolveModPath(modId)
 		if path != "" && steamworks.IsModFolderValid(path) {
 			status = "installed"
+			// Deep check: PBO headers, checksums and signatures
+			if verify && !dayz.VerifyModIntegrity(context.Background(), path).Valid {
+				status = "corrupt"
+			}
 		} else {
 			status = "unknown"
 		}
@@ -570,7 +724,7 @@ func (a *App) CheckMod(modId string, verify bool) (interface{}, error) {
 
 	var progress float64
 	if status == "downloading" {
-		c, t := steamworks.GetDownloadInfo(modId)
+		c, t := a.steam.GetDownloadInfo(modId)
 		if t > 0 {
 			progress = (float64(c) / float64(t)) * 100
 		}
@@ -579,66 +733,187 @@ func (a *App) CheckMod(modId string, verify bool) (interface{}, error) {
 	return map[string]interface{}{"success": true, "status": status, "progress": progress, "stateFlags": state}, nil
 }
 
-func (a *App) FetchModDetails(modIds []string, light bool) (interface{}, error) {
-	// Use Steam Web API to fetch details
-	apiURL := "https://api.steampowered.com/ISteamRemoteStorage/GetPublishedFileDetails/v1/"
+// VerifyModIntegrity runs a deep PBO/signature scan over the given mods.
+// If redownload is set, every mod that fails is re-queued at high priority.
+func (a *App) VerifyModIntegrity(modIds []string, redownload bool) (interface{}, error) {
+	results := make([]*dayz.ModIntegrityResult, 0, len(modIds))
+	failed := 0
 
-	form := url.Values{}
-	form.Add("itemcount", fmt.Sprintf("%d", len(modIds)))
 	for i, id := range modIds {
-		form.Add(fmt.Sprintf("publishedfileids[%d]", i), id)
-	}
+		path := a.steam.ResolveModPath(id)
 
-	resp, err := a.httpClient.PostForm(apiURL, form)
-	if err != nil {
-		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+		var res *dayz.ModIntegrityResult
+		if path == "" {
+			res = &dayz.ModIntegrityResult{Problems: []dayz.ModProblem{{File: id, Reason: "mod not installed"}}}
+		} else {
+			res = dayz.VerifyModIntegrity(context.Background(), path)
+		}
+		res.ModID = id
+
+		if !res.Valid {
+			failed++
+			fmt.Printf("[App] Integrity check failed for %s: %d problem(s)\n", id, len(res.Problems))
+			if redownload {
+				res.RedownloadQueued = a.steam.DownloadItem(id, true)
+				a.markDownloadsActive()
+			}
+		}
+		results = append(results, res)
+
+		a.emit("mod-integrity-progress", map[string]interface{}{
+			"modId":   id,
+			"valid":   res.Valid,
+			"checked": i + 1,
+			"total":   len(modIds),
+		})
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return map[string]interface{}{"success": false, "error": err.Error()}, nil
-	}
-	// fmt.Println("[App] FetchModDetails RAW JSON:", string(body)) // DEBUG LOG REMOVED
+	return map[string]interface{}{"success": true, "results": results, "failed": failed}, nil
+}
 
-	// Structs moved to package level
-	var data ResponseContainer
-	if err := json.Unmarshal(body, &data); err != nil {
-		return map[string]interface{}{"success": false, "error": "JSON Parse Error: " + err.Error()}, nil
+func (a *App) FetchModDetails(modIds []string, light bool) (interface{}, error) {
+	var details []workshop.PublishedFileDetails
+	var err error
+	if light {
+		details, err = a.modCache.Lookup(modIds, 0)
+	} else {
+		details, err = a.modCache.LookupWithDescriptions(modIds, 0)
+	}
+	if err != nil && len(details) == 0 {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
 	}
-	fmt.Printf("[App] Unmarshaled Data Count: %d (Light Mode: %t)\n", len(data.Response.PublishedFileDetails), light) // DEBUG LOG
+	fmt.Printf("[App] Mod Details Count: %d (Light Mode: %t, Stale: %t)\n", len(details), light, err != nil) // DEBUG LOG
 
 	// OPTIMIZATION: If Light mode, strip invalid mods and HEAVY descriptions
 	if light {
-		var clean []PublishedFileDetails
-		for _, item := range data.Response.PublishedFileDetails {
+		var clean []workshop.PublishedFileDetails
+		for _, item := range details {
 			// Strip Description to save massive bandwidth/memory
 			item.Description = ""
 			clean = append(clean, item)
 		}
 		// Replace original list with lightweight list
-		data.Response.PublishedFileDetails = clean
+		details = clean
 	}
 
 	// Transform to match sidecar format (it just passes "details: []")
 	// The sidecar mapped it: title, publishedfileid, etc.
 	// Our struct does that.
-	return map[string]interface{}{"success": true, "details": data.Response.PublishedFileDetails}, nil
+	return map[string]interface{}{"success": true, "details": details, "stale": err != nil}, nil
+}
+
+func (a *App) cachedModTitle(modId string) (string, bool) {
+	d, ok := a.modCache.Get(modId)
+	return d.Title, ok && d.Title != ""
+}
+
+func (a *App) cachedModSize(modId string) uint64 {
+	d, _ := a.modCache.Get(modId)
+	return d.Size()
+}
+
+func (a *App) cachedModUpdated(modId string) int64 {
+	d, _ := a.modCache.Get(modId)
+	return d.TimeUpdated
+}
+
+// prefetchModInfo looks up unknown mods in the background so the next
+// download update can show real titles
+func (a *App) prefetchModInfo(modIds []string) {
+	a.modInfoMu.Lock()
+	var todo []string
+	for _, id := range modIds {
+		if !a.modInfoPending[id] {
+			a.modInfoPending[id] = true
+			todo = append(todo, id)
+		}
+	}
+	a.modInfoMu.Unlock()
+
+	if len(todo) == 0 {
+		return
+	}
+	go func() {
+		if _, err := a.modCache.Lookup(todo, 0); err != nil {
+			fmt.Printf("[App] Mod title lookup failed: %v\n", err)
+		}
+		a.modInfoMu.Lock()
+		for _, id := range todo {
+			delete(a.modInfoPending, id)
+		}
+		a.modInfoMu.Unlock()
+	}()
+}
+
+// CheckOutdatedMods compares installed mods against their Workshop
+// time_updated instead of trusting GetItemState. With force set, every
+// outdated mod is pushed to the front of the Steam download queue.
+func (a *App) CheckOutdatedMods(modIds []string, force bool) (interface{}, error) {
+	results, err := a.findOutdatedMods(modIds, force)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	outdated := 0
+	for _, r := range results {
+		if r.Outdated {
+			outdated++
+		}
+	}
+	return map[string]interface{}{"success": true, "mods": results, "outdated": outdated}, nil
+}
+
+func (a *App) findOutdatedMods(modIds []string, force bool) ([]*dayz.ModFreshness, error) {
+	// time_updated must be recent for this to mean anything
+	details, err := a.modCache.Lookup(modIds, outdatedCheckMaxAge)
+	if err != nil && len(details) == 0 {
+		return nil, err
+	}
+
+	updated := make(map[string]int64, len(details))
+	for _, d := range details {
+		updated[d.PublishedFileId] = d.TimeUpdated
+	}
+
+	results := make([]*dayz.ModFreshness, 0, len(modIds))
+	for _, id := range modIds {
+		path, _, installedAt := a.steam.GetItemInstallInfo(id)
+		if path == "" {
+			// Not installed at all - handled by the missing mod flow
+			continue
+		}
+
+		res := dayz.CheckFreshness(id, path, int64(installedAt), updated[id])
+		if res.WrongMod {
+			fmt.Printf("[App] Mod folder for %s holds a different mod (%s)\n", id, path)
+		}
+		if res.Outdated {
+			fmt.Printf("[App] Mod %s is outdated (installed %d, workshop %d, via %s)\n", id, res.InstalledAt, res.UpdatedAt, res.Source)
+			if force {
+				res.UpdateQueued = a.steam.DownloadItem(id, true)
+				a.markDownloadsActive()
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
 }
 
 func (a *App) OpenModFolder(modId string) (interface{}, error) {
-	path := steamworks.ResolveModPath(modId)
+	path := a.steam.ResolveModPath(modId)
 	if path != "" {
-		exec.Command("explorer", path).Start()
+		if err := opener.Open(path); err != nil {
+			return map[string]interface{}{"success": false, "error": err.Error()}, nil
+		}
 		return map[string]interface{}{"success": true}, nil
 	}
 	return map[string]interface{}{"success": false, "error": "Path not found"}, nil
 }
 
 func (a *App) DeleteMod(modId string) (interface{}, error) {
-	err := steamworks.UnsubscribeMod(modId)
+	err := a.steam.UnsubscribeMod(modId)
 	// Force delete files
-	path := steamworks.ResolveModPath(modId)
+	path := a.steam.ResolveModPath(modId)
 	if path != "" {
 		os.RemoveAll(path)
 	}
@@ -647,7 +922,7 @@ func (a *App) DeleteMod(modId string) (interface{}, error) {
 
 func (a *App) GetDayZVersion() (interface{}, error) {
 	// Use PowerShell to get DayZ version
-	path := steamworks.GetAppInstallDir(221100)
+	path := a.steam.GetAppInstallDir(dayz.AppID)
 	if path == "" {
 		return map[string]interface{}{"success": false, "error": "DayZ not found"}, nil
 	}
@@ -692,45 +967,18 @@ func (a *App) CheckTwitchStream(channel string) (interface{}, error) {
 func (a *App) LaunchGame(ip string, port int, mods []string, name string, launchParams string, discordEnabled bool, serverName string) (interface{}, error) {
 	fmt.Printf("[App] LaunchGame Called: IP=%s Port=%d Mods=%d Name=%s Params=%s DLC=%t ServerName=%s\n", ip, port, len(mods), name, launchParams, discordEnabled, serverName)
 
-	// Update Discord Status
-	go func() {
-		// Prevent crash if Discord panic occurs
-		defer func() {
-			if r := recover(); r != nil {
-				fmt.Printf("[App] Recovered from Discord panic in LaunchGame: %v\n", r)
-			}
-		}()
-
-		if discordEnabled {
-			// Use provided serverName if available, else fallback
-			finalServerName := serverName
-			if finalServerName == "" {
-				finalServerName = fmt.Sprintf("%s:%d", ip, port)
-				// Try to fetch real name
-				if info, err := a.FetchServerInfo(ip, port, 2000); err == nil {
-					if n, ok := info["name"].(string); ok {
-						finalServerName = n
-					}
-				}
-			}
-			fmt.Printf("[App] Updating Discord Status: Playing on %s\n", finalServerName)
-			err := discord.UpdatePresence("Playing DayZ", "Server: "+finalServerName, "logo", "DayZ")
-			if err != nil {
-				fmt.Printf("[App] Failed to update Discord presence: %v\n", err)
-			}
-		} else {
-			// Generic Status if hidden
-			discord.UpdatePresence("Playing DayZ", "In Game", "logo", "DayZ")
+	// Default to the account's survivor name, then the Steam name
+	if name == "" {
+		if p, ok := a.currentProfile(); ok && p.SurvivorName != "" {
+			name = p.SurvivorName
 		}
-	}()
-
-	// Default to Steam Name if custom name is empty
+	}
 	if name == "" {
 		// Use cached name or get from native Steamworks
-		if a.lastPersonaName != "" {
-			name = a.lastPersonaName
+		if cached := a.cachedPersonaName(); cached != "" {
+			name = cached
 		} else {
-			name = steamworks.GetPersonaName()
+			name = a.steam.GetPersonaName()
 		}
 
 		if name != "" {
@@ -738,34 +986,31 @@ func (a *App) LaunchGame(ip string, port int, mods []string, name string, launch
 		}
 	}
 
+	// Mods are Workshop IDs or local mod references, resolved in server order.
+	// Freshness is the join job's concern (see validateJoinMods).
+	modPaths, unresolved := a.resolveLaunchMods(ip, port, mods)
+	if len(unresolved) > 0 {
+		// Launching without them only gets the player kicked on join
+		return map[string]interface{}{
+			"success":    false,
+			"error":      fmt.Sprintf("%d mod(s) couldn't be found: %s", len(unresolved), strings.Join(unresolved, ", ")),
+			"unresolved": unresolved,
+		}, nil
+	}
+
 	// 1. Resolve Mods (while sidecar is running)
 	var modStr, gamePath string
 
 	if UseNativeSteamworks {
-		gamePath = steamworks.GetAppInstallDir(221100)
-		// Fallback paths
+		gamePath = a.steam.GetAppInstallDir(dayz.AppID)
+		// Fallback: find the library holding DayZ ourselves
 		if gamePath == "" {
-			common := []string{
-				`C:\Program Files (x86)\Steam\steamapps\common\DayZ`,
-				`C:\Program Files\Steam\steamapps\common\DayZ`,
-				`D:\SteamLibrary\steamapps\common\DayZ`,
-			}
-			for _, p := range common {
-				if _, err := os.Stat(p); err == nil {
-					gamePath = p
-					break
-				}
+			if steam, err := steamlocate.Locate(); err == nil {
+				gamePath = steam.AppInstallDir(dayz.AppID)
 			}
 		}
 
 		if len(mods) > 0 {
-			var modPaths []string
-			for _, id := range mods {
-				p := steamworks.ResolveModPath(id)
-				if p != "" {
-					modPaths = append(modPaths, p)
-				}
-			}
 			if len(modPaths) > 0 {
 				modStr = fmt.Sprintf(`"-mod=%s"`, filepath.Join(modPaths...))
 				// Wait, Join joins with separator? No, filepath.Join uses path separator.
@@ -849,15 +1094,14 @@ func (a *App) LaunchGame(ip string, port int, mods []string, name string, launch
 			return nil, err
 		}
 
-		// Monitor Game Process
+		// DayZ_BE.exe only bootstraps BattlEye and the game; reap it, and
+		// follow DayZ_x64.exe itself in watchGameSession
 		go func() {
 			if err := cmd.Wait(); err != nil {
-				fmt.Printf("[App] Game process exited with error: %v\n", err)
+				fmt.Printf("[App] %s exited with error: %v\n", filepath.Base(exePath), err)
 			} else {
-				fmt.Println("[App] Game process exited normally.")
+				fmt.Printf("[App] %s exited\n", filepath.Base(exePath))
 			}
-			// Reset Discord Presence
-			discord.UpdatePresence("Browsing Servers", "In Launcher", "logo", "DayZ Launcher")
 		}()
 
 	} else {
@@ -869,16 +1113,49 @@ func (a *App) LaunchGame(ip string, port int, mods []string, name string, launch
 			nameArg = fmt.Sprintf(" \"-name=%s\"", name)
 		}
 
-		launchUrl := fmt.Sprintf("steam://run/221100//-connect=%s -port=%d%s -noSplash -noPause -skipIntro -world=empty -noBenchmark %s", ip, port, nameArg, modStr)
+		launchUrl := fmt.Sprintf("steam://run/%d//-connect=%s -port=%d%s -noSplash -noPause -skipIntro -world=empty -noBenchmark %s", dayz.AppID, ip, port, nameArg, modStr)
 		fmt.Printf("[App] Launching URL: %s\n", launchUrl)
 
-		cmd := exec.Command("rundll32", "url.dll,FileProtocolHandler", launchUrl)
-		if err := cmd.Start(); err != nil {
+		if err := opener.Open(launchUrl); err != nil {
 			fmt.Printf("[App] Failed to launch steam protocol: %v\n", err)
 			return nil, err
 		}
 	}
 
+	// Update Discord Status now that the game is actually starting
+	go func() {
+		// Prevent crash if Discord panic occurs
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("[App] Recovered from Discord panic in LaunchGame: %v\n", r)
+			}
+		}()
+
+		if discordEnabled {
+			// Use provided serverName if available, else fallback
+			finalServerName := serverName
+			if finalServerName == "" {
+				finalServerName = fmt.Sprintf("%s:%d", ip, port)
+				// Try to fetch real name
+				if info, err := a.FetchServerInfo(ip, port, 2000); err == nil {
+					if n, ok := info["name"].(string); ok {
+						finalServerName = n
+					}
+				}
+			}
+			fmt.Printf("[App] Updating Discord Status: Playing on %s\n", finalServerName)
+			err := discord.UpdatePresence("Playing DayZ", "Server: "+finalServerName, "logo", "DayZ")
+			if err != nil {
+				fmt.Printf("[App] Failed to update Discord presence: %v\n", err)
+			}
+		} else {
+			// Generic Status if hidden
+			discord.UpdatePresence("Playing DayZ", "In Game", "logo", "DayZ")
+		}
+	}()
+
+	go a.watchGameSession(a.publishSessionPresence(ip, port, serverName))
+
 	return map[string]interface{}{"success": true}, nil
 }
 
@@ -891,32 +1168,101 @@ func (a *App) isDiscordProcessRunning() bool {
 }
 
 func (a *App) VerifyServerMods(ip string, port int) (interface{}, error) {
+	res := a.verifyServer(ip, port)
+	if res.Success {
+		return res, nil
+	}
+
+	// Both attempts failed
+	return map[string]interface{}{"success": false, "error": res.Error}, nil
+}
+
+// verifyServer queries the server's mod list, retrying once with a longer timeout
+func (a *App) verifyServer(ip string, port int) *dayz.VerificationResult {
 	// Attempt 1: 2 second timeout
 	res := dayz.VerifyMods(ip, port, 2)
 
+	if !res.Success {
+		// Attempt 2: 3 second timeout
+		res = dayz.VerifyMods(ip, port, 3)
+	}
+
 	if res.Success {
-		return res, nil
+		a.rememberServerMods(ip, res.GamePort, res.Mods)
+		a.annotateModStatus(res)
+		a.checkMapDLC(res)
 	}
+	return res
+}
 
-	// Attempt 2: 3 second timeout
-	res = dayz.VerifyMods(ip, port, 3)
+// annotateModStatus tags each server mod with its Workshop status and marks
+// the result blocked if any of them can't be downloaded
+func (a *App) annotateModStatus(res *dayz.VerificationResult) {
+	ids := make([]string, 0, len(res.Mods))
+	for _, m := range res.Mods {
+		ids = append(ids, m.WorkshopID)
+	}
+	if len(ids) == 0 {
+		return
+	}
 
-	if res.Success {
-		return res, nil
+	details, err := a.modCache.Lookup(ids, 0)
+	if err != nil && len(details) == 0 {
+		fmt.Printf("[App] Workshop status lookup failed: %v\n", err)
+		return
+	}
+	byID := make(map[string]*workshop.PublishedFileDetails, len(details))
+	for i := range details {
+		byID[details[i].PublishedFileId] = &details[i]
 	}
 
-	// Both attempts failed
-	return map[string]interface{}{"success": false, "error": res.Error}, nil
+	locals := a.GetLocalMods()
+	var unusable []string
+	for i := range res.Mods {
+		m := &res.Mods[i]
+		d, ok := byID[m.WorkshopID]
+		if !ok {
+			continue
+		}
+		status := d.Status()
+		m.Status = string(status)
+		if status.Usable() || status == workshop.StatusUnknown {
+			continue
+		}
+		m.StatusReason = status.Reason()
+
+		// A registered local copy still makes the join possible
+		if local, _ := localModFor(locals, *m); local != nil {
+			continue
+		}
+		unusable = append(unusable, fmt.Sprintf("%s (%s)", m.Name, m.StatusReason))
+	}
+
+	if len(unusable) > 0 {
+		res.Blocked = true
+		res.BlockReason = "Required mods are unavailable: " + strings.Join(unusable, ", ")
+	}
 }
 
 // -- MAP CACHE METHODS --
 
-func ensureMapCacheDir() string {
+// ensureConfigDir returns the launcher's settings folder, creating it if needed
+func ensureConfigDir() string {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return ""
 	}
-	cacheDir := filepath.Join(configDir, "han-launcher", "map_cache")
+	dir := filepath.Join(configDir, "han-launcher")
+	os.MkdirAll(dir, 0755)
+	return dir
+}
+
+func ensureMapCacheDir() string {
+	configDir := ensureConfigDir()
+	if configDir == "" {
+		return ""
+	}
+	cacheDir := filepath.Join(configDir, "map_cache")
 	os.MkdirAll(cacheDir, 0755)
 	return cacheDir
 }
@@ -1000,21 +1346,3 @@ func (a *App) SaveMapCache(mapName string, url string) (map[string]interface{},
 func joinMods(paths []string) string {
 	return strings.Join(paths, ";")
 }
-
-// -- STRUCTS --
-
-type PublishedFileDetails struct {
-	PublishedFileId string `json:"publishedfileid"`
-	Title           string `json:"title"`
-	FileSize        string `json:"file_size"`
-	TimeUpdated     int64  `json:"time_updated"`
-	Creator         string `json:"creator"`
-	PreviewUrl      string `json:"preview_url"`
-	Description     string `json:"description,omitempty"`
-}
-
-type ResponseContainer struct {
-	Response struct {
-		PublishedFileDetails []PublishedFileDetails `json:"publishedfiledetails"`
-	} `json:"response"`
-}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamworks"
+	"dayz-launcher-go/internal/steamworks/steamfake"
+)
+
+const (
+	cfModID   = "1559212036"
+	expModID  = "1564026768"
+	cfModSize = 250
+)
+
+// newTestApp builds the App on a running, connected fake Steam. The config
+// dir and home point at a temp dir so nothing touches the real profile.
+func newTestApp(t *testing.T) (*App, *steamfake.Client) {
+	t.Helper()
+	tmp := t.TempDir()
+	for _, env := range []string{"HOME", "APPDATA", "XDG_CONFIG_HOME", "XDG_DATA_HOME"} {
+		t.Setenv(env, tmp)
+	}
+
+	fake := steamfake.New()
+	fake.SetInstallRoot(filepath.Join(tmp, "workshop", "content", "221100"))
+	fake.SetDetails(steamworks.UGCDetails{PublishedFileID: 1559212036, Title: "Community Framework", FileSize: cfModSize, TimeUpdated: 1700000000})
+	fake.SetDetails(steamworks.UGCDetails{PublishedFileID: 1564026768, Title: "Community Online Tools", FileSize: cfModSize, TimeUpdated: 1700000000})
+
+	a := newApp(fake)
+	if err := a.steam.Init(); err != nil {
+		t.Fatal(err)
+	}
+	// Warm the title cache so download listings don't look them up in the background
+	if out, _ := a.FetchModDetails([]string{cfModID, expModID}, true); !succeeded(out) {
+		t.Fatalf("FetchModDetails() = %v", out)
+	}
+	return a, fake
+}
+
+type emitted struct {
+	name string
+	data interface{}
+}
+
+// eventLog records what the App sends to the frontend
+type eventLog struct {
+	mu     sync.Mutex
+	events []emitted
+}
+
+func recordEvents(a *App) *eventLog {
+	log := &eventLog{}
+	a.onEmit = func(event string, data interface{}) {
+		log.mu.Lock()
+		log.events = append(log.events, emitted{event, data})
+		log.mu.Unlock()
+	}
+	return log
+}
+
+// named returns the payloads of every event with the given name
+func (l *eventLog) named(name string) []interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []interface{}
+	for _, e := range l.events {
+		if e.name == name {
+			out = append(out, e.data)
+		}
+	}
+	return out
+}
+
+func succeeded(out interface{}) bool {
+	m, ok := out.(map[string]interface{})
+	return ok && m["success"] == true
+}
+
+func activeDownloads(t *testing.T, a *App) (data []map[string]interface{}, meta map[string]interface{}) {
+	t.Helper()
+	out, ok := a.GetActiveDownloads().(map[string]interface{})
+	if !ok {
+		t.Fatalf("GetActiveDownloads() = %v", out)
+	}
+	data, _ = out["data"].([]map[string]interface{})
+	meta, _ = out["meta"].(map[string]interface{})
+	return data, meta
+}
+
+func checkMod(t *testing.T, a *App, modId string) map[string]interface{} {
+	t.Helper()
+	out, _ := a.CheckMod(modId, false)
+	return out.(map[string]interface{})
+}
+
+func TestSubscribeDownloadInstall(t *testing.T) {
+	a, fake := newTestApp(t)
+
+	if out, _ := a.SubscribeWorkshop(cfModID); !succeeded(out) {
+		t.Fatalf("SubscribeWorkshop() = %v", out)
+	}
+	if got := a.GetSubscribedMods(); !reflect.DeepEqual(got, []string{cfModID}) {
+		t.Errorf("GetSubscribedMods() = %v", got)
+	}
+	if got := checkMod(t, a, cfModID)["status"]; got != "queued" {
+		t.Errorf("status after subscribing = %v, want queued", got)
+	}
+
+	// The subscription callback wakes the throttled download loop
+	a.setDownloadsActive(false)
+	a.lastDownloadScan = time.Now()
+	fake.RunCallbacks()
+	if !a.shouldPollDownloads(time.Now()) {
+		t.Error("download loop still idle after the subscription callback")
+	}
+
+	fake.SetProgress(cfModID, 50, 200)
+	data, meta := activeDownloads(t, a)
+	if len(data) != 1 {
+		t.Fatalf("active downloads = %v", data)
+	}
+	if d := data[0]; d["id"] != cfModID || d["name"] != "Community Framework" || d["status"] != "downloading" || d["progress"] != 25.0 {
+		t.Errorf("download = %v", d)
+	}
+	if meta["connected"] != true || meta["name"] != "Survivor" {
+		t.Errorf("meta = %v", meta)
+	}
+	if m := checkMod(t, a, cfModID); m["status"] != "downloading" || m["progress"] != 25.0 {
+		t.Errorf("CheckMod() while downloading = %v", m)
+	}
+
+	// Steam places the files, then reports the install
+	fake.Install(cfModID, 1700000000)
+	item, _ := fake.Item(cfModID)
+	if err := os.MkdirAll(filepath.Join(item.Path, "addons"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(item.Path, "addons", "cf.pbo"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fake.RunCallbacks()
+
+	if data, _ := activeDownloads(t, a); len(data) != 0 {
+		t.Errorf("active downloads after install = %v", data)
+	}
+	if got := checkMod(t, a, cfModID)["status"]; got != "installed" {
+		t.Errorf("status after install = %v, want installed", got)
+	}
+
+	if out, _ := a.UnsubscribeWorkshop(cfModID); !succeeded(out) {
+		t.Fatalf("UnsubscribeWorkshop() = %v", out)
+	}
+	if got := a.GetSubscribedMods(); len(got) != 0 {
+		t.Errorf("GetSubscribedMods() after unsubscribing = %v", got)
+	}
+}
+
+func TestDownloadQueueProgress(t *testing.T) {
+	a, fake := newTestApp(t)
+	for _, id := range []string{cfModID, expModID} {
+		fake.SetItem(id, steamfake.Item{Total: cfModSize})
+	}
+
+	if out, _ := a.QueueServerMods([]string{cfModID, expModID}); !succeeded(out) {
+		t.Fatalf("QueueServerMods() = %v", out)
+	}
+	want := []steamfake.DownloadRequest{{ModID: cfModID, HighPriority: true}, {ModID: expModID, HighPriority: true}}
+	if got := fake.DownloadRequests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DownloadRequests() = %v, want %v", got, want)
+	}
+	if got := a.GetSubscribedMods(); len(got) != 2 {
+		t.Errorf("GetSubscribedMods() = %v, want both mods", got)
+	}
+
+	// Steam moves 100 bytes per callback run: 100, 200, then installed
+	fake.SetDownloadRate(100)
+	steps := []struct {
+		status    string
+		current   uint64
+		completed int
+	}{
+		{dlStatusDownloading, 100, 0},
+		{dlStatusDownloading, 200, 0},
+		{dlStatusInstalled, cfModSize, 2},
+	}
+	for i, step := range steps {
+		fake.RunCallbacks()
+		a.downloads.Poll()
+
+		for _, job := range a.downloads.Snapshot() {
+			if job.Status != step.status || job.Current != step.current || !job.Priority {
+				t.Errorf("step %d: job = %+v, want %s at %d bytes", i, job, step.status, step.current)
+			}
+		}
+		p := a.joinProgress()
+		if p["mods"] != 2 || p["completed"] != step.completed || p["current"] != 2*step.current || p["total"] != uint64(2*cfModSize) {
+			t.Errorf("step %d: joinProgress() = %v", i, p)
+		}
+	}
+	if a.downloads.Active() {
+		t.Error("queue still active after every mod installed")
+	}
+	if data, _ := activeDownloads(t, a); len(data) != 0 {
+		t.Errorf("active downloads = %v", data)
+	}
+}
+
+func TestSteamGoesOffline(t *testing.T) {
+	a, fake := newTestApp(t)
+	if out, _ := a.SubscribeWorkshop(cfModID); !succeeded(out) {
+		t.Fatalf("SubscribeWorkshop() = %v", out)
+	}
+	fake.SetProgress(cfModID, 50, 200)
+
+	fake.SetRunning(false)
+
+	status := a.GetSteamStatus().(map[string]interface{})
+	if status["connected"] != false {
+		t.Errorf("GetSteamStatus() = %v, want disconnected", status)
+	}
+	if _, meta := activeDownloads(t, a); meta["connected"] != false || meta["offline"] != true {
+		t.Errorf("GetActiveDownloads() meta = %v, want the offline listing", meta)
+	}
+	if m := checkMod(t, a, cfModID); m["offline"] != true {
+		t.Errorf("CheckMod() = %v, want the offline status", m)
+	}
+	if out, _ := a.SubscribeWorkshop(expModID); succeeded(out) {
+		t.Errorf("SubscribeWorkshop() while offline = %v", out)
+	}
+
+	// Queueing while Steam is away gets nowhere, but mustn't hold up the
+	// retry once it's back
+	a.QueueServerMods([]string{expModID})
+	if got := fake.DownloadRequests(); len(got) != 0 {
+		t.Errorf("DownloadRequests() while offline = %v", got)
+	}
+
+	fake.SetRunning(true)
+	if err := a.steam.Init(); err != nil {
+		t.Fatal(err)
+	}
+	a.QueueServerMods([]string{expModID})
+	want := []steamfake.DownloadRequest{{ModID: expModID, HighPriority: true}}
+	if got := fake.DownloadRequests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DownloadRequests() after reconnecting = %v, want %v", got, want)
+	}
+
+	status = a.GetSteamStatus().(map[string]interface{})
+	if status["connected"] != true || status["name"] != "Survivor" {
+		t.Errorf("GetSteamStatus() after reconnecting = %v", status)
+	}
+	if got := checkMod(t, a, cfModID)["status"]; got != "downloading" {
+		t.Errorf("status after reconnecting = %v, want downloading", got)
+	}
+}
+
+// waitForMessage polls the job until its message matches or the deadline passes
+func waitForMessage(t *testing.T, job *joinJob, message string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job.mu.Lock()
+		got := job.status.Message
+		job.mu.Unlock()
+		if got == message {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("join never reported %q", message)
+}
+
+func TestJoinWaitsForSteam(t *testing.T) {
+	a, fake := newTestApp(t)
+	fake.SetRunning(false)
+
+	job := &joinJob{done: make(chan struct{})}
+	result := make(chan bool, 1)
+	go func() {
+		result <- a.waitForMods(context.Background(), job, []string{cfModID}, false)
+	}()
+	waitForMessage(t, job, "Waiting for Steam to reconnect")
+
+	fake.SetRunning(true)
+	if err := a.steam.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// On the next tick the job re-queues the mod, and finishes once it's in
+	deadline := time.After(5 * time.Second)
+	for len(fake.DownloadRequests()) == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("mod was never re-queued after Steam came back")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	fake.Install(cfModID, 1700000000)
+
+	select {
+	case ok := <-result:
+		if !ok {
+			t.Errorf("waitForMods() = false, status %+v", job.status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitForMods() didn't return after the mod was installed")
+	}
+}
+
+func TestJoinCancelledWhileOffline(t *testing.T) {
+	a, fake := newTestApp(t)
+	fake.SetRunning(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &joinJob{done: make(chan struct{})}
+	result := make(chan bool, 1)
+	go func() {
+		result <- a.waitForMods(ctx, job, []string{cfModID}, false)
+	}()
+	waitForMessage(t, job, "Waiting for Steam to reconnect")
+	cancel()
+
+	select {
+	case ok := <-result:
+		if ok {
+			t.Error("waitForMods() = true after cancelling")
+		}
+		if job.status.Phase != joinPhaseCancelled {
+			t.Errorf("phase = %q, want %q", job.status.Phase, joinPhaseCancelled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("waitForMods() didn't return after cancelling")
+	}
+}
+
+func TestAnnotateModStatus(t *testing.T) {
+	a, fake := newTestApp(t)
+	mods := []struct {
+		id      uint64
+		name    string
+		details steamworks.UGCDetails
+		want    string
+	}{
+		{1559212036, "CF", steamworks.UGCDetails{Result: 1, ConsumerAppID: 221100}, "ok"},
+		{1000000001, "Gone", steamworks.UGCDetails{Result: 9}, "removed"},
+		{1000000002, "Secret", steamworks.UGCDetails{Result: 1, Visibility: 2, ConsumerAppID: 221100}, "hidden"},
+		{1000000003, "Naughty", steamworks.UGCDetails{Result: 1, Banned: true, ConsumerAppID: 221100}, "banned"},
+		{1000000004, "Skyrim", steamworks.UGCDetails{Result: 1, ConsumerAppID: 72850}, "wrong_app"},
+	}
+
+	res := &dayz.VerificationResult{Success: true}
+	for _, m := range mods {
+		d := m.details
+		d.PublishedFileID, d.Title = m.id, m.name
+		fake.SetDetails(d)
+		res.Mods = append(res.Mods, dayz.Mod{Name: m.name, WorkshopID: strconv.FormatUint(m.id, 10)})
+	}
+	a.annotateModStatus(res)
+
+	for i, m := range mods {
+		if got := res.Mods[i].Status; got != m.want {
+			t.Errorf("%s: status = %q, want %q", m.name, got, m.want)
+		}
+	}
+	if !res.Blocked {
+		t.Fatal("join with unusable mods isn't blocked")
+	}
+	for _, m := range mods[1:] {
+		if !strings.Contains(res.BlockReason, m.name+" (") {
+			t.Errorf("BlockReason doesn't list %s: %s", m.name, res.BlockReason)
+		}
+	}
+	if strings.Contains(res.BlockReason, "CF") {
+		t.Errorf("BlockReason lists the usable mod: %s", res.BlockReason)
+	}
+
+	ok := &dayz.VerificationResult{Success: true, Mods: res.Mods[:1]}
+	a.annotateModStatus(ok)
+	if ok.Blocked {
+		t.Errorf("join blocked with only usable mods: %s", ok.BlockReason)
+	}
+}
+
+func TestLocalCopyOfRemovedMod(t *testing.T) {
+	a, fake := newTestApp(t)
+	const ip, port, goneID = "127.0.0.1", 2302, "1000000001"
+	fake.SetDetails(steamworks.UGCDetails{PublishedFileID: 1000000001, Title: "Gone", Result: 9})
+
+	// No meta.cpp, so only the server's display name can match it
+	dir := filepath.Join(t.TempDir(), "@Gone")
+	if err := os.MkdirAll(filepath.Join(dir, "addons"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "addons", "gone.pbo"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := a.AddLocalMod(dir); !succeeded(out) {
+		t.Fatalf("AddLocalMod() = %v", out)
+	}
+
+	res := &dayz.VerificationResult{Success: true, Mods: []dayz.Mod{{Name: "Gone", WorkshopID: goneID}}}
+	a.rememberServerMods(ip, port, res.Mods)
+	a.annotateModStatus(res)
+	if res.Blocked {
+		t.Fatalf("join blocked despite the local copy: %s", res.BlockReason)
+	}
+
+	paths, unresolved := a.resolveLaunchMods(ip, port, []string{goneID})
+	if len(unresolved) != 0 || len(paths) != 1 || paths[0] != filepath.Clean(dir) {
+		t.Errorf("resolveLaunchMods() = %v, %v, want the local copy", paths, unresolved)
+	}
+
+	// Another server's "Gone" may be a different mod entirely
+	if _, unresolved := a.resolveLaunchMods(ip, 2402, []string{goneID}); len(unresolved) != 1 {
+		t.Errorf("resolved %s without that server's mod list", goneID)
+	}
+	out, _ := a.LaunchGame(ip, 2402, []string{goneID}, "", "", false, "")
+	if succeeded(out) {
+		t.Errorf("LaunchGame() = %v, want refusal", out)
+	}
+}
+
+func TestVerifyModIntegrityProgress(t *testing.T) {
+	a, _ := newTestApp(t)
+	events := recordEvents(a)
+
+	out, _ := a.VerifyModIntegrity([]string{cfModID, expModID}, false)
+	if res := out.(map[string]interface{}); res["failed"] != 2 {
+		t.Errorf("VerifyModIntegrity() = %v, want both missing mods failed", res)
+	}
+
+	progress := events.named("mod-integrity-progress")
+	if len(progress) != 2 {
+		t.Fatalf("progress events = %v, want one per mod", progress)
+	}
+	for i, id := range []string{cfModID, expModID} {
+		ev := progress[i].(map[string]interface{})
+		if ev["modId"] != id || ev["valid"] != false || ev["checked"] != i+1 || ev["total"] != 2 {
+			t.Errorf("progress %d = %v", i, ev)
+		}
+	}
+}
+
+func TestValidateJoinModsLeavesQueueingToRequire(t *testing.T) {
+	a, fake := newTestApp(t)
+	// Installed according to Steam, but nothing on disk
+	fake.SetItem(cfModID, steamfake.Item{State: steamfake.StateSubscribed | steamfake.StateInstalled, Path: filepath.Join(t.TempDir(), cfModID)})
+
+	broken := a.validateJoinMods(context.Background(), []string{cfModID})
+	if !reflect.DeepEqual(broken, []string{cfModID}) {
+		t.Fatalf("validateJoinMods() = %v, want the empty mod", broken)
+	}
+	if got := fake.DownloadRequests(); len(got) != 0 {
+		t.Errorf("validation issued downloads itself: %v", got)
+	}
+
+	a.downloads.Require(broken, true)
+	a.downloads.Require(broken, true) // the cooldown holds back a second request
+	want := []steamfake.DownloadRequest{{ModID: cfModID, HighPriority: true}}
+	if got := fake.DownloadRequests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DownloadRequests() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+type avatarKey struct {
+	steamID uint64
+	size    steamworks.AvatarSize
+}
+
+// avatarEntry is a rendered avatar. Steam hands out a new image handle when
+// an avatar changes, so the handle doubles as its hash.
+type avatarEntry struct {
+	handle int32
+	url    string
+}
+
+func avatarSizeName(size steamworks.AvatarSize) string {
+	if size == steamworks.AvatarMedium {
+		return "medium"
+	}
+	return "small"
+}
+
+// friendAvatar returns a user's avatar as a PNG data URL. pending means
+// Steam is still downloading it and a "friend-avatar" event will follow.
+func (a *App) friendAvatar(steamID uint64, size steamworks.AvatarSize) (url string, pending bool) {
+	handle := a.steam.GetFriendAvatar(steamID, size)
+	if handle == steamworks.AvatarNone {
+		// Every Steam user has an avatar, so 0 means it isn't loaded yet.
+		// Remember the request; avatarChanged renders it once Steam has it.
+		a.steam.RequestUserInformation(steamID, false)
+		key := avatarKey{steamID, size}
+		a.avatarMu.Lock()
+		if _, ok := a.avatars[key]; !ok {
+			a.avatars[key] = avatarEntry{}
+		}
+		a.avatarMu.Unlock()
+		return "", true
+	}
+	url, err := a.renderAvatar(steamID, size, handle)
+	if err != nil {
+		fmt.Printf("[App] Avatar for %d: %v\n", steamID, err)
+	}
+	return url, false
+}
+
+// renderAvatar converts image handle to a data URL, reusing the cached one
+// if the handle hasn't changed
+func (a *App) renderAvatar(steamID uint64, size steamworks.AvatarSize, handle int32) (string, error) {
+	key := avatarKey{steamID, size}
+	a.avatarMu.Lock()
+	e, ok := a.avatars[key]
+	a.avatarMu.Unlock()
+	if ok && e.handle == handle {
+		return e.url, nil
+	}
+
+	img, err := a.steam.GetImage(handle)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	url := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	a.avatarMu.Lock()
+	a.avatars[key] = avatarEntry{handle: handle, url: url}
+	a.avatarMu.Unlock()
+	return url, nil
+}
+
+// GetFriendAvatars returns avatars as PNG data URLs keyed by SteamID.
+// Avatars Steam hasn't downloaded yet are listed under "pending" and
+// arrive later as "friend-avatar" events.
+func (a *App) GetFriendAvatars(steamIds []string, medium bool) (interface{}, error) {
+	if !a.steam.IsInitialized() {
+		return map[string]interface{}{"success": false, "error": "Steam not initialized"}, nil
+	}
+	size := steamworks.AvatarSmall
+	if medium {
+		size = steamworks.AvatarMedium
+	}
+
+	avatars := make(map[string]string, len(steamIds))
+	pending := []string{}
+	for _, idStr := range steamIds {
+		id, err := steamid.Parse(idStr)
+		if err != nil {
+			continue
+		}
+		url, loading := a.friendAvatar(id.Uint64(), size)
+		if loading {
+			pending = append(pending, id.String())
+		} else if url != "" {
+			avatars[id.String()] = url
+		}
+	}
+	return map[string]interface{}{"success": true, "avatars": avatars, "pending": pending}, nil
+}
+
+// avatarLoaded renders an avatar Steam just finished downloading
+func (a *App) avatarLoaded(ev steamworks.AvatarImageLoaded) {
+	// AvatarImageLoaded_t doesn't say which size was asked for; small
+	// avatars are 32px, medium 64px
+	size := steamworks.AvatarSmall
+	if ev.Width > 32 {
+		size = steamworks.AvatarMedium
+	}
+	a.publishAvatar(ev.SteamID, size, ev.Image)
+}
+
+// avatarChanged re-renders the sizes the frontend asked for after a
+// PersonaStateChange with the avatar flag, e.g. once a requested user's
+// information arrives
+func (a *App) avatarChanged(steamID uint64) {
+	for _, size := range []steamworks.AvatarSize{steamworks.AvatarSmall, steamworks.AvatarMedium} {
+		a.avatarMu.Lock()
+		e, ok := a.avatars[avatarKey{steamID, size}]
+		a.avatarMu.Unlock()
+		if !ok {
+			continue
+		}
+		handle := a.steam.GetFriendAvatar(steamID, size)
+		if handle == steamworks.AvatarNone || handle == e.handle {
+			continue
+		}
+		a.publishAvatar(steamID, size, handle)
+	}
+}
+
+func (a *App) publishAvatar(steamID uint64, size steamworks.AvatarSize, handle int32) {
+	url, err := a.renderAvatar(steamID, size, handle)
+	if err != nil {
+		fmt.Printf("[App] Avatar for %d: %v\n", steamID, err)
+		return
+	}
+	a.emit("friend-avatar", map[string]interface{}{
+		"steamId": steamid.ID(steamID),
+		"size":    avatarSizeName(size),
+		"avatar":  url,
+	})
+}
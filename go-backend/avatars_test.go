@@ -0,0 +1,61 @@
+package main
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+func TestFriendAvatarLoadsLater(t *testing.T) {
+	a, fake := newTestApp(t)
+	events := recordEvents(a)
+	friend := steamid.FromAccountID(22202)
+
+	// Steam hasn't fetched the image: the avatar is pending, not missing
+	fake.SetAvatarUnloaded(friend.Uint64())
+	out, _ := a.GetFriendAvatars([]string{friend.String()}, false)
+	res := out.(map[string]interface{})
+	if got := res["pending"]; !reflect.DeepEqual(got, []string{friend.String()}) {
+		t.Errorf("pending = %v, want the friend", got)
+	}
+	if got := res["avatars"].(map[string]string); len(got) != 0 {
+		t.Errorf("avatars = %v, want none yet", got)
+	}
+	if got := fake.RequestedUsers(); !reflect.DeepEqual(got, []uint64{friend.Uint64()}) {
+		t.Errorf("RequestedUsers() = %v, want the friend", got)
+	}
+
+	// Steam delivers it with PersonaStateChange
+	fake.SetAvatar(friend.Uint64(), image.NewRGBA(image.Rect(0, 0, 32, 32)))
+	fake.RunCallbacks()
+
+	published := events.named("friend-avatar")
+	if len(published) != 1 {
+		t.Fatalf("friend-avatar events = %v, want one", published)
+	}
+	ev := published[0].(map[string]interface{})
+	url, _ := ev["avatar"].(string)
+	if ev["steamId"] != friend || ev["size"] != "small" || !strings.HasPrefix(url, "data:image/png;base64,") {
+		t.Errorf("friend-avatar = %v", ev)
+	}
+
+	out, _ = a.GetFriendAvatars([]string{friend.String()}, false)
+	res = out.(map[string]interface{})
+	if got := res["avatars"].(map[string]string)[friend.String()]; got != url {
+		t.Errorf("avatar after loading = %.40q, want the published one", got)
+	}
+	if got := res["pending"].([]string); len(got) != 0 {
+		t.Errorf("pending after loading = %v", got)
+	}
+
+	// A repeated change with the same image doesn't publish it again
+	fake.Emit(steamworks.PersonaStateChange{SteamID: friend.Uint64(), ChangeFlags: steamworks.PersonaChangeAvatar})
+	fake.RunCallbacks()
+	if got := len(events.named("friend-avatar")); got != 1 {
+		t.Errorf("unchanged avatar published again: %d events", got)
+	}
+}
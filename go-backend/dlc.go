@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"dayz-launcher-go/internal/dayz"
+)
+
+// dlcStatus asks Steam whether the user owns dlc. Ownership covers DLC
+// that isn't downloaded yet; Steam fetches it when the game starts.
+func (a *App) dlcStatus(dlc dayz.DLC) dayz.DLCStatus {
+	return dayz.DLCStatus{
+		DLC:       dlc,
+		Owned:     a.steam.IsSubscribedApp(dlc.AppID) || a.steam.IsDlcInstalled(dlc.AppID),
+		Installed: a.steam.IsDlcInstalled(dlc.AppID),
+	}
+}
+
+// checkMapDLC blocks the join when the server's map needs a DLC the user
+// doesn't own, instead of letting the game fail after loading. Without
+// Steam, or when its ownership calls aren't available, ownership is
+// unknown, so nothing is blocked.
+func (a *App) checkMapDLC(res *dayz.VerificationResult) {
+	dlc, ok := dayz.DLCForMap(res.Map)
+	if !ok || !a.steam.HasDLCChecks() {
+		return
+	}
+	status := a.dlcStatus(dlc)
+	res.RequiredDLC = &status
+	if status.Owned {
+		return
+	}
+
+	fmt.Printf("[App] Server map %s requires %s (app %d), which isn't owned\n", res.Map, dlc.Name, dlc.AppID)
+	reason := fmt.Sprintf("Requires DLC you don't own: %s", dlc.Name)
+	if res.Blocked && res.BlockReason != "" {
+		reason = res.BlockReason + "; " + reason
+	}
+	res.Blocked = true
+	res.BlockReason = reason
+}
+
+// GetOwnedDLC lists the map DLCs with the user's ownership, plus the maps
+// they can play, for filtering the server browser
+func (a *App) GetOwnedDLC() (interface{}, error) {
+	if !a.steam.IsInitialized() {
+		return map[string]interface{}{"success": false, "error": "Steam not initialized"}, nil
+	}
+	if !a.steam.HasDLCChecks() {
+		// Reporting every DLC as unowned would lock maps the user can play
+		return map[string]interface{}{"success": false, "error": "DLC ownership unavailable"}, nil
+	}
+	dlcs := make([]dayz.DLCStatus, 0, len(dayz.MapDLCs))
+	owned := []uint32{}
+	lockedMaps := []string{}
+	for _, dlc := range dayz.MapDLCs {
+		status := a.dlcStatus(dlc)
+		dlcs = append(dlcs, status)
+		if status.Owned {
+			owned = append(owned, dlc.AppID)
+		} else {
+			lockedMaps = append(lockedMaps, dlc.Maps...)
+		}
+	}
+	return map[string]interface{}{"success": true, "dlc": dlcs, "owned": owned, "lockedMaps": lockedMaps}, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"dayz-launcher-go/internal/dayz"
+)
+
+func TestCheckMapDLC(t *testing.T) {
+	tests := []struct {
+		name        string
+		owned       bool
+		checks      bool
+		wantBlocked bool
+	}{
+		{"owned", true, true, false},
+		{"not owned", false, true, true},
+		{"ownership unavailable", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, fake := newTestApp(t)
+			fake.SetDLC(1151700, tt.owned, false)
+			fake.SetDLCChecks(tt.checks)
+
+			res := &dayz.VerificationResult{Success: true, Map: "Enoch.dayzOffline"}
+			a.checkMapDLC(res)
+			if res.Blocked != tt.wantBlocked {
+				t.Errorf("Blocked = %t (%s), want %t", res.Blocked, res.BlockReason, tt.wantBlocked)
+			}
+			if out, _ := a.GetOwnedDLC(); succeeded(out) != tt.checks {
+				t.Errorf("GetOwnedDLC() = %v", out)
+			}
+		})
+	}
+}
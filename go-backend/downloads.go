@@ -0,0 +1,364 @@
+package main
+
+import (
+	"dayz-launcher-go/internal/steamworks"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EItemState flags from ISteamUGC
+const (
+	itemStateSubscribed      = 1
+	itemStateInstalled       = 4
+	itemStateNeedsUpdate     = 8
+	itemStateDownloading     = 16
+	itemStateDownloadPending = 32
+)
+
+const (
+	// No byte progress for this long on an active download counts as a stall
+	downloadStallTimeout = 30 * time.Second
+	// Minimum gap between high priority re-issues for the same mod
+	downloadPriorityCooldown = 45 * time.Second
+	// Give up re-issuing after this many attempts and report the mod as failed
+	downloadMaxRetries = 5
+)
+
+// Download job states
+const (
+	dlStatusQueued      = "queued"
+	dlStatusDownloading = "downloading"
+	dlStatusStalled     = "stalled"
+	dlStatusInstalled   = "installed"
+	dlStatusFailed      = "failed"
+)
+
+// downloadJob tracks one mod the launcher needs on disk
+type downloadJob struct {
+	ModID        string    `json:"id"`
+	Priority     bool      `json:"priority"`
+	Status       string    `json:"status"`
+	Current      uint64    `json:"current"`
+	Total        uint64    `json:"total"`
+	Retries      int       `json:"retries"`
+	StateFlags   uint32    `json:"stateFlags"`
+	AddedAt      time.Time `json:"addedAt"`
+	lastProgress time.Time
+	lastBytes    uint64
+}
+
+// downloadManager owns the queue of required mods. Mods for the server the
+// user is joining are flagged priority and get re-issued to Steam when their
+// progress stalls, rate limited by a per-mod cooldown.
+type downloadManager struct {
+	mu        sync.Mutex
+	jobs      map[string]*downloadJob
+	cooldowns map[string]time.Time
+	steam     steamworks.SteamClient
+	emit      func(event string, data interface{})
+}
+
+func newDownloadManager(steam steamworks.SteamClient, emit func(event string, data interface{})) *downloadManager {
+	return &downloadManager{
+		jobs:      make(map[string]*downloadJob),
+		cooldowns: make(map[string]time.Time),
+		steam:     steam,
+		emit:      emit,
+	}
+}
+
+// Require adds mods to the queue, subscribing to any the user doesn't have yet
+func (m *downloadManager) Require(modIds []string, priority bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range modIds {
+		job, ok := m.jobs[id]
+		if !ok {
+			job = &downloadJob{ModID: id, Status: dlStatusQueued, AddedAt: now, lastProgress: now}
+			m.jobs[id] = job
+		}
+		if priority {
+			job.Priority = true
+		}
+		if job.Status == dlStatusFailed {
+			// Explicitly requested again, give it a fresh set of retries
+			job.Status = dlStatusQueued
+			job.Retries = 0
+		}
+
+		state := m.steam.GetItemState(id)
+		if state&itemStateSubscribed == 0 {
+			if err := m.steam.SubscribeMod(id); err != nil {
+				fmt.Printf("[Downloads] Subscribe %s failed: %v\n", id, err)
+			}
+		}
+		if job.Priority {
+			m.kickLocked(job, now)
+		}
+	}
+}
+
+// SetPriority makes the given mods the only high priority ones, e.g. when
+// the user switches to joining a different server.
+func (m *downloadManager) SetPriority(modIds []string) {
+	m.mu.Lock()
+	for _, job := range m.jobs {
+		job.Priority = false
+	}
+	m.mu.Unlock()
+
+	m.Require(modIds, true)
+}
+
+// Prioritize re-issues a single mod at high priority, honouring the cooldown
+func (m *downloadManager) Prioritize(modId string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[modId]
+	if !ok {
+		now := time.Now()
+		job = &downloadJob{ModID: modId, Status: dlStatusQueued, AddedAt: now, lastProgress: now}
+		m.jobs[modId] = job
+	}
+	job.Priority = true
+	return m.kickLocked(job, time.Now())
+}
+
+// kickLocked asks Steam to download the mod now unless we did so recently.
+// A request Steam refuses (e.g. while it's offline) doesn't start the cooldown.
+func (m *downloadManager) kickLocked(job *downloadJob, now time.Time) bool {
+	if until, ok := m.cooldowns[job.ModID]; ok && now.Before(until) {
+		return false
+	}
+	if !m.steam.DownloadItem(job.ModID, true) {
+		return false
+	}
+	m.cooldowns[job.ModID] = now.Add(downloadPriorityCooldown)
+	return true
+}
+
+// Clear drops finished jobs from the queue
+func (m *downloadManager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, job := range m.jobs {
+		if job.Status == dlStatusInstalled || job.Status == dlStatusFailed {
+			delete(m.jobs, id)
+			delete(m.cooldowns, id)
+		}
+	}
+}
+
+// Poll refreshes every job from Steam, handles stalls and emits status events
+func (m *downloadManager) Poll() {
+	m.mu.Lock()
+	if len(m.jobs) == 0 {
+		m.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	var changed []downloadJob
+
+	for _, job := range m.jobs {
+		prevStatus, prevBytes := job.Status, job.Current
+
+		state := m.steam.GetItemState(job.ModID)
+		job.StateFlags = state
+		current, total := m.steam.GetDownloadInfo(job.ModID)
+		if total > 0 {
+			job.Current, job.Total = current, total
+		}
+
+		switch {
+		case state&itemStateInstalled != 0 && state&(itemStateNeedsUpdate|itemStateDownloading|itemStateDownloadPending) == 0:
+			job.Status = dlStatusInstalled
+			job.Current = job.Total
+		case job.Status == dlStatusFailed:
+			// Stays failed until required again
+		default:
+			if state&itemStateDownloading != 0 {
+				job.Status = dlStatusDownloading
+			} else if job.Status != dlStatusStalled {
+				job.Status = dlStatusQueued
+			}
+
+			if job.Current != job.lastBytes {
+				job.lastBytes = job.Current
+				job.lastProgress = now
+				if job.Status == dlStatusStalled {
+					job.Status = dlStatusDownloading
+				}
+			}
+
+			// Only priority mods are expected to move; others may just be waiting their turn
+			stalled := now.Sub(job.lastProgress) > downloadStallTimeout
+			if stalled && (job.Priority || state&itemStateDownloading != 0) {
+				job.Status = dlStatusStalled
+				if job.Priority {
+					if job.Retries >= downloadMaxRetries {
+						job.Status = dlStatusFailed
+						fmt.Printf("[Downloads] Giving up on %s after %d retries\n", job.ModID, job.Retries)
+					} else if m.kickLocked(job, now) {
+						job.Retries++
+						job.lastProgress = now
+						fmt.Printf("[Downloads] Re-issued stalled download %s (attempt %d)\n", job.ModID, job.Retries)
+					}
+				}
+			}
+		}
+
+		if job.Status != prevStatus || job.Current != prevBytes {
+			changed = append(changed, *job)
+		}
+	}
+
+	summary := m.summaryLocked()
+	m.mu.Unlock()
+
+	if m.emit == nil {
+		return
+	}
+	for _, job := range changed {
+		m.emit("download-mod-status", job)
+	}
+	m.emit("download-queue-status", summary)
+}
+
+// Active reports whether any job is still waiting on Steam
+func (m *downloadManager) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.Status != dlStatusInstalled && job.Status != dlStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadFailed handles a failed DownloadItemResult by treating the job as
+// stalled, so the next Poll re-issues it (cooldown permitting)
+func (m *downloadManager) DownloadFailed(modId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[modId]; ok && job.Status != dlStatusFailed {
+		job.lastProgress = time.Time{}
+	}
+}
+
+// Snapshot returns the current queue, priority mods first
+func (m *downloadManager) Snapshot() []downloadJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]downloadJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority
+		}
+		return jobs[i].AddedAt.Before(jobs[j].AddedAt)
+	})
+	return jobs
+}
+
+func (m *downloadManager) summaryLocked() map[string]interface{} {
+	counts := map[string]int{}
+	var current, total uint64
+	for _, job := range m.jobs {
+		counts[job.Status]++
+		current += job.Current
+		total += job.Total
+	}
+	return map[string]interface{}{
+		"total":       len(m.jobs),
+		"installed":   counts[dlStatusInstalled],
+		"downloading": counts[dlStatusDownloading],
+		"queued":      counts[dlStatusQueued],
+		"stalled":     counts[dlStatusStalled],
+		"failed":      counts[dlStatusFailed],
+		"current":     current,
+		"totalBytes":  total,
+	}
+}
+
+// -- DOWNLOAD METHODS --
+
+// QueueServerMods makes the given server's mods the top download priority
+func (a *App) QueueServerMods(modIds []string) (interface{}, error) {
+	a.downloads.SetPriority(modIds)
+	return map[string]interface{}{"success": true, "queued": len(modIds)}, nil
+}
+
+// GetDownloadQueue returns the managed download queue
+func (a *App) GetDownloadQueue() interface{} {
+	return map[string]interface{}{"success": true, "data": a.downloads.Snapshot()}
+}
+
+// ClearDownloadQueue forgets installed and failed jobs
+func (a *App) ClearDownloadQueue() (interface{}, error) {
+	a.downloads.Clear()
+	return map[string]interface{}{"success": true}, nil
+}
+
+// joinProgress aggregates the priority mods, i.e. everything the pending join
+// still needs. Sizes fall back to the Workshop listing for queued mods that
+// Steam hasn't reported a total for yet.
+func (a *App) joinProgress() map[string]interface{} {
+	var current, total uint64
+	var speed float64
+	mods, completed := 0, 0
+
+	for _, job := range a.downloads.Snapshot() {
+		if !job.Priority {
+			continue
+		}
+		mods++
+
+		size := job.Total
+		if size == 0 {
+			size = a.cachedModSize(job.ModID)
+		}
+		cur := job.Current
+		if job.Status == dlStatusInstalled {
+			completed++
+			cur = size
+		}
+		if cur > size {
+			size = cur
+		}
+		current += cur
+		total += size
+		speed += a.dlStats.Speed(job.ModID)
+	}
+
+	if mods == 0 {
+		return nil
+	}
+
+	var progress float64
+	if total > 0 {
+		progress = float64(current) / float64(total) * 100
+	} else {
+		progress = float64(completed) / float64(mods) * 100
+	}
+
+	return map[string]interface{}{
+		"mods":      mods,
+		"completed": completed,
+		"current":   current,
+		"total":     total,
+		"progress":  progress,
+		"speed":     speed,
+		"eta":       etaSeconds(total-current, speed),
+	}
+}
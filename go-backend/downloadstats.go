@@ -0,0 +1,101 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// Speed is averaged over this much history to smooth out Steam's bursty reporting
+	speedWindow = 10 * time.Second
+	// Samples closer together than this are dropped (the UI and ticker both poll)
+	minSampleGap = 250 * time.Millisecond
+)
+
+type byteSample struct {
+	at    time.Time
+	bytes uint64
+}
+
+// downloadStats keeps a sliding window of byte counts per mod
+type downloadStats struct {
+	mu      sync.Mutex
+	samples map[string][]byteSample
+}
+
+func newDownloadStats() *downloadStats {
+	return &downloadStats{samples: make(map[string][]byteSample)}
+}
+
+// Observe records the current byte count of a download
+func (s *downloadStats) Observe(modId string, current uint64, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.samples[modId]
+	if n := len(list); n > 0 {
+		last := list[n-1]
+		if current < last.bytes {
+			// Steam restarted the download (new manifest), start over
+			list = nil
+		} else if now.Sub(last.at) < minSampleGap {
+			// Crediting these bytes to last.at would overstate the speed
+			return
+		}
+	}
+
+	list = append(list, byteSample{at: now, bytes: current})
+
+	// Drop samples that fell out of the window, keeping one as the baseline
+	cut := 0
+	for cut < len(list)-1 && now.Sub(list[cut+1].at) >= speedWindow {
+		cut++
+	}
+	s.samples[modId] = list[cut:]
+}
+
+// Speed returns the average bytes/second across the window
+func (s *downloadStats) Speed(modId string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.samples[modId]
+	if len(list) < 2 {
+		return 0
+	}
+	first, last := list[0], list[len(list)-1]
+	elapsed := last.at.Sub(first.at).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(last.bytes-first.bytes) / elapsed
+}
+
+// Forget drops the history of a finished download
+func (s *downloadStats) Forget(modId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.samples, modId)
+}
+
+// Prune drops the history of every mod not in the active set
+func (s *downloadStats) Prune(active map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.samples {
+		if !active[id] {
+			delete(s.samples, id)
+		}
+	}
+}
+
+// etaSeconds returns the time left at the given speed, or -1 if unknown
+func etaSeconds(remaining uint64, speed float64) int64 {
+	if remaining == 0 {
+		return 0
+	}
+	if speed <= 0 {
+		return -1
+	}
+	return int64(float64(remaining)/speed + 0.5)
+}
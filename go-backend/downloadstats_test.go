@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDownloadSpeed(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		every time.Duration
+	}{
+		{"slow polling", time.Second},
+		{"fast polling", 100 * time.Millisecond},
+		{"polling at the gap", minSampleGap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A steady 1000 bytes/s for 5 seconds
+			s := newDownloadStats()
+			for at := time.Duration(0); at <= 5*time.Second; at += tt.every {
+				s.Observe("1", uint64(at.Milliseconds()), start.Add(at))
+			}
+			if got := s.Speed("1"); math.Abs(got-1000) > 1 {
+				t.Errorf("Speed() = %.1f, want 1000", got)
+			}
+		})
+	}
+}
+
+func TestDownloadSpeedRestart(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	s := newDownloadStats()
+	s.Observe("1", 0, start)
+	s.Observe("1", 5000, start.Add(time.Second))
+
+	// Steam started over, e.g. for a new manifest
+	s.Observe("1", 100, start.Add(2*time.Second))
+	if got := s.Speed("1"); got != 0 {
+		t.Errorf("Speed() right after a restart = %.1f, want 0", got)
+	}
+	s.Observe("1", 600, start.Add(3*time.Second))
+	if got := s.Speed("1"); got != 500 {
+		t.Errorf("Speed() = %.1f, want 500", got)
+	}
+}
+
+func TestEtaSeconds(t *testing.T) {
+	tests := []struct {
+		remaining uint64
+		speed     float64
+		want      int64
+	}{
+		{0, 0, 0},
+		{1000, 0, -1},
+		{1000, 300, 3},
+		{1000, 400, 3},
+	}
+	for _, tt := range tests {
+		if got := etaSeconds(tt.remaining, tt.speed); got != tt.want {
+			t.Errorf("etaSeconds(%d, %.0f) = %d, want %d", tt.remaining, tt.speed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamid"
+)
+
+// Per-port A2S timeout when guessing a friend's query port
+const queryPortProbeTimeout = 2 * time.Second
+
+// JoinFriend joins the server a friend is playing on. The friend's address
+// comes from Steam; req supplies the launch settings (name, params, Discord).
+// It then runs the same verify, download and launch job as JoinWhenReady.
+func (a *App) JoinFriend(steamIdString string, req JoinRequest) (interface{}, error) {
+	steamID, err := steamid.Parse(steamIdString)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	var found bool
+	var name, ip string
+	var gamePort, queryPort int
+	for _, f := range a.steam.GetFriends() {
+		if f.SteamID == steamID {
+			found = true
+			name, ip, gamePort, queryPort = f.Name, f.GameIP, f.GamePort, f.QueryPort
+			break
+		}
+	}
+	if !found {
+		return map[string]interface{}{"success": false, "error": "Friend not found"}, nil
+	}
+	if ip == "" || gamePort == 0 {
+		return map[string]interface{}{"success": false, "error": fmt.Sprintf("%s is not on a DayZ server", name)}, nil
+	}
+
+	fmt.Printf("[App] JoinFriend: joining %s on %s:%d\n", name, ip, gamePort)
+	res, err := a.joinByGamePort(ip, gamePort, queryPort, req)
+	if m, ok := res.(map[string]interface{}); ok {
+		m["friend"] = name
+	}
+	return res, err
+}
+
+// joinByGamePort starts a join job for a server known only by its game
+// port, as Steam reports it. A missing query port is probed for.
+func (a *App) joinByGamePort(ip string, gamePort, queryPort int, req JoinRequest) (interface{}, error) {
+	if queryPort == 0 {
+		fmt.Printf("[App] No query port for %s:%d, probing\n", ip, gamePort)
+		var err error
+		queryPort, err = dayz.ProbeQueryPort(ip, gamePort, queryPortProbeTimeout)
+		if err != nil {
+			return map[string]interface{}{"success": false, "error": "Could not reach the server: " + err.Error()}, nil
+		}
+	}
+
+	req.IP = ip
+	req.QueryPort = queryPort
+	res, err := a.JoinWhenReady(req)
+	if m, ok := res.(map[string]interface{}); ok {
+		m["queryPort"] = queryPort
+	}
+	return res, err
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"dayz-launcher-go/internal/a2s"
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+const (
+	// Friends' servers are re-queried at most this often
+	friendServerTTL     = 30 * time.Second
+	friendServerTimeout = 2 * time.Second
+)
+
+// friendServer is the cached A2S view of a server one or more friends are on
+type friendServer struct {
+	IP        string
+	GamePort  int
+	QueryPort int
+	Info      *a2s.ServerInfo
+	Err       error
+	FetchedAt time.Time
+
+	done chan struct{} // closed once the query has finished
+}
+
+func serverKey(ip string, port int) string {
+	return ip + ":" + strconv.Itoa(port)
+}
+
+// lookupFriendServer returns the cached info for ip:gamePort, querying the
+// server if it's stale. Concurrent lookups of one server share a query.
+func (a *App) lookupFriendServer(ip string, gamePort, queryPort int) *friendServer {
+	key := serverKey(ip, gamePort)
+
+	a.friendSrvMu.Lock()
+	if e := a.friendServers[key]; e != nil {
+		select {
+		case <-e.done:
+			if time.Since(e.FetchedAt) < friendServerTTL {
+				a.friendSrvMu.Unlock()
+				return e
+			}
+			if queryPort == 0 {
+				queryPort = e.QueryPort
+			}
+		default:
+			a.friendSrvMu.Unlock()
+			<-e.done
+			return e
+		}
+	}
+	e := &friendServer{IP: ip, GamePort: gamePort, QueryPort: queryPort, done: make(chan struct{})}
+	a.friendServers[key] = e
+	a.friendSrvMu.Unlock()
+
+	defer close(e.done)
+	if e.QueryPort == 0 {
+		port, err := dayz.ProbeQueryPort(ip, gamePort, friendServerTimeout)
+		if err != nil {
+			e.Err, e.FetchedAt = err, time.Now()
+			return e
+		}
+		e.QueryPort = port
+	}
+	e.Info, e.Err = a2s.QueryInfo(ip, e.QueryPort, friendServerTimeout)
+	e.FetchedAt = time.Now()
+	return e
+}
+
+// friendsByServer groups friends playing DayZ by server (ip:gamePort)
+func friendsByServer(friends []steamworks.SteamFriend) map[string][]steamworks.SteamFriend {
+	groups := make(map[string][]steamworks.SteamFriend)
+	for _, f := range friends {
+		if !f.IsPlaying || f.GameIP == "" || f.GamePort == 0 {
+			continue
+		}
+		key := serverKey(f.GameIP, f.GamePort)
+		groups[key] = append(groups[key], f)
+	}
+	return groups
+}
+
+// GetFriendServers resolves the servers friends are playing on through A2S.
+// Each server is listed once with the friends on it.
+func (a *App) GetFriendServers() (interface{}, error) {
+	if !a.steam.IsInitialized() {
+		return map[string]interface{}{"success": false, "error": "Steam not initialized"}, nil
+	}
+	groups := friendsByServer(a.steam.GetFriends())
+
+	results := make([]*friendServer, 0, len(groups))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, group := range groups {
+		wg.Add(1)
+		go func(f steamworks.SteamFriend) {
+			defer wg.Done()
+			e := a.lookupFriendServer(f.GameIP, f.GamePort, f.QueryPort)
+			mu.Lock()
+			results = append(results, e)
+			mu.Unlock()
+		}(group[0])
+	}
+	wg.Wait()
+	a.pruneFriendServers(groups)
+
+	servers := make([]map[string]interface{}, 0, len(results))
+	counts := make(map[string]int)
+	for _, e := range results {
+		group := groups[serverKey(e.IP, e.GamePort)]
+		friends := make([]map[string]interface{}, 0, len(group))
+		for _, f := range group {
+			friends = append(friends, map[string]interface{}{
+				"steamId": f.SteamID,
+				"name":    f.Name,
+			})
+		}
+
+		srv := map[string]interface{}{
+			"ip":          e.IP,
+			"gamePort":    e.GamePort,
+			"queryPort":   e.QueryPort,
+			"friends":     friends,
+			"friendCount": len(group),
+			"online":      e.Info != nil,
+		}
+		if e.Info != nil {
+			srv["name"] = e.Info.Name
+			srv["map"] = e.Info.Map
+			srv["players"] = e.Info.Players
+			srv["maxPlayers"] = e.Info.MaxPlayers
+			srv["ping"] = e.Info.Latency
+			srv["password"] = e.Info.Password
+			srv["version"] = e.Info.Version
+			srv["tags"] = a2s.ParseDayZTags(e.Info.Tags)
+		} else if e.Err != nil {
+			srv["error"] = e.Err.Error()
+		}
+		servers = append(servers, srv)
+
+		counts[serverKey(e.IP, e.GamePort)] = len(group)
+		if e.QueryPort != 0 {
+			counts[serverKey(e.IP, e.QueryPort)] = len(group)
+		}
+	}
+
+	// Servers with the most friends first
+	sort.SliceStable(servers, func(i, j int) bool {
+		ci, cj := servers[i]["friendCount"].(int), servers[j]["friendCount"].(int)
+		if ci != cj {
+			return ci > cj
+		}
+		return fmt.Sprint(servers[i]["name"]) < fmt.Sprint(servers[j]["name"])
+	})
+
+	return map[string]interface{}{"success": true, "servers": servers, "counts": counts}, nil
+}
+
+// GetFriendCounts maps "ip:port" to the number of friends on that server,
+// without querying anything. Both the game port and, when known, the query
+// port are listed so the server browser can match either.
+func (a *App) GetFriendCounts() map[string]int {
+	counts := make(map[string]int)
+	if !a.steam.IsInitialized() {
+		return counts
+	}
+	for key, group := range friendsByServer(a.steam.GetFriends()) {
+		counts[key] = len(group)
+
+		queryPort := group[0].QueryPort
+		if queryPort == 0 {
+			a.friendSrvMu.Lock()
+			if e := a.friendServers[key]; e != nil {
+				select {
+				case <-e.done:
+					queryPort = e.QueryPort
+				default:
+				}
+			}
+			a.friendSrvMu.Unlock()
+		}
+		if queryPort != 0 {
+			counts[serverKey(group[0].GameIP, queryPort)] = len(group)
+		}
+	}
+	return counts
+}
+
+// pruneFriendServers drops finished, expired entries no friend is on anymore
+func (a *App) pruneFriendServers(active map[string][]steamworks.SteamFriend) {
+	a.friendSrvMu.Lock()
+	defer a.friendSrvMu.Unlock()
+	for key, e := range a.friendServers {
+		if _, ok := active[key]; ok {
+			continue
+		}
+		select {
+		case <-e.done:
+			if time.Since(e.FetchedAt) >= friendServerTTL {
+				delete(a.friendServers, key)
+			}
+		default:
+		}
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/ebitengine/purego v0.9.1
 	github.com/wailsapp/wails/v2 v2.11.0
 	github.com/woozymasta/a2s v0.3.0
+	golang.org/x/sys v0.35.0
 )
 
 require (
@@ -35,7 +36,6 @@ require (
 	github.com/woozymasta/steam v0.1.3 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
-	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
 )
 
@@ -20,6 +20,9 @@ const (
 	A2S_PLAYER_RESP   = 0x44
 )
 
+// Used when a caller passes no timeout
+const defaultTimeout = 3 * time.Second
+
 type Player struct {
 	Index    uint8   `json:"index"`
 	Name     string  `json:"name"`
@@ -39,15 +42,18 @@ type ServerInfo struct {
 	Latency     int64  `json:"latency"`
 }
 
-func QueryInfo(ip string, port int) (*ServerInfo, error) {
+func QueryInfo(ip string, port int, timeout time.Duration) (*ServerInfo, error) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
 	address := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", address, 2*time.Second)
+	conn, err := net.DialTimeout("udp", address, timeout)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
-	if err := conn.SetDeadline(time.Now().Add(3 * time.Second)); err != nil {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, err
 	}
 
@@ -318,15 +324,18 @@ func parseRulesPayload(b []byte) (map[string]string, error) {
 	return rules, nil
 }
 
-func QueryPlayers(ip string, port int) ([]*Player, error) {
+func QueryPlayers(ip string, port int, timeout time.Duration) ([]*Player, error) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
 	address := fmt.Sprintf("%s:%d", ip, port)
-	conn, err := net.DialTimeout("udp", address, 2*time.Second)
+	conn, err := net.DialTimeout("udp", address, timeout)
 	if err != nil {
 		return nil, err
 	}
 	defer conn.Close()
 
-	if err := conn.SetDeadline(time.Now().Add(3 * time.Second)); err != nil {
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,75 @@
+package a2s
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DayZTags is the keywords field of a DayZ server's A2S_INFO reply, e.g.
+// "battleye,no3rd,external,privHive,shard,lqs0,etm4.000000,entm2.000000,mod,15:23"
+type DayZTags struct {
+	BattlEye    bool     `json:"battleye"`
+	ThirdPerson bool     `json:"thirdPerson"`
+	External    bool     `json:"external"`
+	PrivateHive bool     `json:"privateHive"`
+	Shard       bool     `json:"shard"`
+	Modded      bool     `json:"modded"`
+	QueueSize   int      `json:"queueSize"`
+	DayAccel    float64  `json:"dayAccel,omitempty"`   // etm: day time acceleration
+	NightAccel  float64  `json:"nightAccel,omitempty"` // entm: night time acceleration
+	Time        string   `json:"time,omitempty"`       // in-game time, HH:MM
+	Unknown     []string `json:"unknown,omitempty"`
+}
+
+var clockTag = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+
+// ParseDayZTags decodes the comma separated tags. Tags it doesn't know are
+// kept in Unknown.
+func ParseDayZTags(tags string) DayZTags {
+	t := DayZTags{ThirdPerson: true}
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		switch {
+		case tag == "":
+		case tag == "battleye":
+			t.BattlEye = true
+		case tag == "no3rd":
+			t.ThirdPerson = false
+		case tag == "external":
+			t.External = true
+		case tag == "privHive":
+			t.PrivateHive = true
+		case tag == "shard":
+			t.Shard = true
+		case tag == "mod":
+			t.Modded = true
+		case clockTag.MatchString(tag):
+			t.Time = tag
+		case strings.HasPrefix(tag, "lqs") && parseInt(tag[3:], &t.QueueSize):
+		case strings.HasPrefix(tag, "entm") && parseFloat(tag[4:], &t.NightAccel):
+		case strings.HasPrefix(tag, "etm") && parseFloat(tag[3:], &t.DayAccel):
+		default:
+			t.Unknown = append(t.Unknown, tag)
+		}
+	}
+	return t
+}
+
+func parseInt(s string, v *int) bool {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return false
+	}
+	*v = n
+	return true
+}
+
+func parseFloat(s string, v *float64) bool {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return false
+	}
+	*v = f
+	return true
+}
@@ -0,0 +1,46 @@
+package dayz
+
+import "strings"
+
+// AppID is the Steam app ID of DayZ. Workshop items and Steam callbacks are
+// filtered on it; DLCs have their own IDs.
+const AppID = 221100
+
+// DLC is a paid DayZ expansion that gates a map
+type DLC struct {
+	AppID uint32   `json:"appId"`
+	Name  string   `json:"name"`
+	Maps  []string `json:"maps"` // world names as servers report them
+}
+
+// MapDLCs lists the DLCs a server's map can require. Other DLCs (e.g. Art
+// of War) only add content and don't stop anyone joining.
+var MapDLCs = []DLC{
+	{AppID: 1151700, Name: "Livonia", Maps: []string{"enoch"}},
+	{AppID: 2968040, Name: "Frostline", Maps: []string{"sakhal"}},
+}
+
+// DLCForMap returns the DLC needed to play mapName, if any. Matching is
+// case-insensitive and ignores the mission suffix some servers append
+// (e.g. "enoch.dayzOffline").
+func DLCForMap(mapName string) (DLC, bool) {
+	world := strings.ToLower(strings.TrimSpace(mapName))
+	if i := strings.IndexByte(world, '.'); i >= 0 {
+		world = world[:i]
+	}
+	for _, dlc := range MapDLCs {
+		for _, m := range dlc.Maps {
+			if m == world {
+				return dlc, true
+			}
+		}
+	}
+	return DLC{}, false
+}
+
+// DLCStatus is a DLC and whether the user has it
+type DLCStatus struct {
+	DLC
+	Owned     bool `json:"owned"`
+	Installed bool `json:"installed"`
+}
@@ -0,0 +1,27 @@
+package dayz
+
+import "testing"
+
+func TestDLCForMap(t *testing.T) {
+	tests := []struct {
+		mapName string
+		want    uint32 // 0 when no DLC is needed
+	}{
+		{"enoch", 1151700},
+		{"Enoch", 1151700},
+		{"enoch.dayzOffline", 1151700},
+		{"ENOCH.DayZOffline", 1151700},
+		{" sakhal ", 2968040},
+		{"Sakhal.dayzOffline", 2968040},
+		{"chernarusplus", 0},
+		{"chernarusplus.dayzOffline", 0},
+		{"enochplus", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		dlc, ok := DLCForMap(tt.mapName)
+		if ok != (tt.want != 0) || dlc.AppID != tt.want {
+			t.Errorf("DLCForMap(%q) = %d, %t, want %d", tt.mapName, dlc.AppID, ok, tt.want)
+		}
+	}
+}
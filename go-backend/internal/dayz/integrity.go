@@ -0,0 +1,119 @@
+package dayz
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dayz-launcher-go/internal/pbo"
+)
+
+// ModProblem describes a single integrity failure inside a mod folder
+type ModProblem struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// ModIntegrityResult is the outcome of a deep scan of one mod folder
+type ModIntegrityResult struct {
+	ModID            string       `json:"modId,omitempty"`
+	Path             string       `json:"path"`
+	Valid            bool         `json:"valid"`
+	PboCount         int          `json:"pboCount"`
+	Problems         []ModProblem `json:"problems"`
+	RedownloadQueued bool         `json:"redownloadQueued,omitempty"`
+}
+
+func (r *ModIntegrityResult) addProblem(file, format string, args ...interface{}) {
+	r.Problems = append(r.Problems, ModProblem{File: file, Reason: fmt.Sprintf(format, args...)})
+}
+
+// VerifyModIntegrity walks the addons folder of a mod and checks that every
+// PBO is structurally sound, has an intact SHA-1 footer and a matching .bisign.
+// A cancelled ctx stops the scan between PBOs and leaves the result invalid.
+func VerifyModIntegrity(ctx context.Context, modPath string) *ModIntegrityResult {
+	res := &ModIntegrityResult{Path: modPath, Problems: []ModProblem{}}
+
+	addonsDir := findAddonsDir(modPath)
+	if addonsDir == "" {
+		res.addProblem("addons", "addons folder missing")
+		return res
+	}
+
+	entries, err := os.ReadDir(addonsDir)
+	if err != nil {
+		res.addProblem("addons", "cannot read addons folder: %v", err)
+		return res
+	}
+
+	// Index signatures by lowercase name so "Foo.pbo.key.bisign" matches "foo.pbo"
+	var signatures []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(strings.ToLower(e.Name()), ".bisign") {
+			signatures = append(signatures, strings.ToLower(e.Name()))
+		}
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".pbo") {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			res.addProblem("addons", "scan stopped: %v", err)
+			return res
+		}
+		res.PboCount++
+
+		name := e.Name()
+		if !hasSignature(signatures, name) {
+			res.addProblem(name, "missing .bisign signature")
+		}
+		if err := checkPbo(filepath.Join(addonsDir, name)); err != nil {
+			res.addProblem(name, "%v", err)
+		}
+	}
+
+	if res.PboCount == 0 {
+		res.addProblem("addons", "no .pbo files found")
+	}
+
+	res.Valid = len(res.Problems) == 0
+	return res
+}
+
+// findAddonsDir returns the addons folder regardless of its capitalisation
+func findAddonsDir(modPath string) string {
+	entries, err := os.ReadDir(modPath)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if e.IsDir() && strings.EqualFold(e.Name(), "addons") {
+			return filepath.Join(modPath, e.Name())
+		}
+	}
+	return ""
+}
+
+func hasSignature(signatures []string, pboName string) bool {
+	prefix := strings.ToLower(pboName) + "."
+	for _, s := range signatures {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPbo validates the header table and the trailing SHA-1 of a PBO
+func checkPbo(path string) error {
+	archive, err := pbo.Open(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	return archive.Verify()
+}
@@ -0,0 +1,116 @@
+package dayz
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalMod is an unpacked @Mod folder on disk that isn't managed by the Workshop
+type LocalMod struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	PublishedID string `json:"publishedId,omitempty"` // from meta.cpp if it's a copy of a Workshop mod
+}
+
+// LocalModMatch pairs a server mod with the local folder that satisfies it
+type LocalModMatch struct {
+	Mod      Mod       `json:"mod"`
+	LocalMod *LocalMod `json:"localMod,omitempty"`
+	MatchBy  string    `json:"matchBy,omitempty"` // "publishedId", "name" or "folder"
+}
+
+// NewLocalMod validates a folder and reads its identity from meta.cpp / mod.cpp
+func NewLocalMod(path string) (*LocalMod, error) {
+	path = filepath.Clean(path)
+	if err := ValidateLocalMod(path); err != nil {
+		return nil, err
+	}
+
+	mod := &LocalMod{Path: path, Name: filepath.Base(path)}
+	if meta, err := ReadModMeta(path); err == nil {
+		if meta.Name != "" {
+			mod.Name = meta.Name
+		}
+		mod.PublishedID = meta.PublishedID
+	}
+	return mod, nil
+}
+
+// ValidateLocalMod checks that a folder has an addons directory with at least one PBO
+func ValidateLocalMod(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("folder not found: %s", path)
+	}
+
+	addonsDir := findAddonsDir(path)
+	if addonsDir == "" {
+		return fmt.Errorf("no addons folder in %s", path)
+	}
+
+	entries, err := os.ReadDir(addonsDir)
+	if err != nil {
+		return fmt.Errorf("cannot read addons folder: %v", err)
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.EqualFold(filepath.Ext(e.Name()), ".pbo") {
+			return nil
+		}
+	}
+	return fmt.Errorf("addons folder in %s has no .pbo files", path)
+}
+
+// MatchLocalMod finds the local folder for a server mod. The Workshop ID from
+// meta.cpp is the strongest match, then the display name, then the folder name.
+func MatchLocalMod(locals []LocalMod, mod Mod) (*LocalMod, string) {
+	if mod.WorkshopID != "" && mod.WorkshopID != "0" {
+		for i := range locals {
+			if locals[i].PublishedID == mod.WorkshopID {
+				return &locals[i], "publishedId"
+			}
+		}
+	}
+
+	want := normalizeModName(mod.Name)
+	if want == "" {
+		return nil, ""
+	}
+	for i := range locals {
+		if normalizeModName(locals[i].Name) == want {
+			return &locals[i], "name"
+		}
+	}
+	for i := range locals {
+		if normalizeModName(filepath.Base(locals[i].Path)) == want {
+			return &locals[i], "folder"
+		}
+	}
+	return nil, ""
+}
+
+// FindLocalMod resolves a launch entry that refers to a local mod by
+// name, folder name or full path.
+func FindLocalMod(locals []LocalMod, ref string) *LocalMod {
+	for i := range locals {
+		if strings.EqualFold(filepath.Clean(locals[i].Path), filepath.Clean(ref)) {
+			return &locals[i]
+		}
+	}
+	if m, _ := MatchLocalMod(locals, Mod{Name: ref}); m != nil {
+		return m
+	}
+	return nil
+}
+
+// normalizeModName makes "@CF", "cf" and "C.F." compare equal
+func normalizeModName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@")) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package dayz
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// .NET DateTime.ToBinary stores the kind in the top two bits
+	dotnetTicksMask = 0x3FFFFFFFFFFFFFFF
+	// Ticks between 0001-01-01 and the Unix epoch
+	dotnetUnixEpochTicks = 621355968000000000
+	dotnetTicksPerSecond = 10000000
+
+	// meta.cpp is stamped by the publisher before the upload finishes,
+	// so allow some slack against the Workshop time_updated.
+	metaTimestampSlack = time.Hour
+)
+
+var cppValueRe = regexp.MustCompile(`(?m)^\s*(\w+)\s*=\s*(?:"((?:[^"]|"")*)"|([^;\r\n]*))\s*;`)
+
+// ModMeta is the identity of an installed mod read from meta.cpp / mod.cpp
+type ModMeta struct {
+	PublishedID string `json:"publishedId,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Timestamp   int64  `json:"timestamp,omitempty"` // Unix seconds
+}
+
+// ModFreshness compares an installed mod with its Workshop listing. A
+// folder whose meta.cpp names another mod is WrongMod and also Outdated.
+type ModFreshness struct {
+	ModID        string `json:"modId"`
+	Name         string `json:"name,omitempty"`
+	InstalledAt  int64  `json:"installedAt"`
+	UpdatedAt    int64  `json:"updatedAt"`
+	Source       string `json:"source"` // "steam", "meta" or "none"
+	Outdated     bool   `json:"outdated"`
+	WrongMod     bool   `json:"wrongMod,omitempty"`
+	UpdateQueued bool   `json:"updateQueued,omitempty"`
+}
+
+// ReadModMeta parses meta.cpp and mod.cpp from a mod folder. Values from
+// meta.cpp win; mod.cpp only fills in a missing name.
+func ReadModMeta(modPath string) (*ModMeta, error) {
+	meta := &ModMeta{}
+	found := false
+
+	if data, err := os.ReadFile(filepath.Join(modPath, "meta.cpp")); err == nil {
+		found = true
+		values := ParseCppValues(data)
+		meta.PublishedID = values["publishedid"]
+		meta.Name = values["name"]
+		if raw, ok := values["timestamp"]; ok {
+			meta.Timestamp = parseMetaTimestamp(raw)
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(modPath, "mod.cpp")); err == nil {
+		found = true
+		if meta.Name == "" {
+			meta.Name = ParseCppValues(data)["name"]
+		}
+	}
+
+	if !found {
+		return nil, os.ErrNotExist
+	}
+	return meta, nil
+}
+
+// ParseCppValues extracts top level `key = value;` pairs from a config style
+// file. Keys are lowercased and quoted values are unescaped.
+func ParseCppValues(data []byte) map[string]string {
+	values := make(map[string]string)
+	for _, m := range cppValueRe.FindAllSubmatch(data, -1) {
+		key := strings.ToLower(string(m[1]))
+		if _, exists := values[key]; exists {
+			continue
+		}
+		if m[3] != nil {
+			values[key] = strings.TrimSpace(string(m[3]))
+		} else {
+			values[key] = strings.ReplaceAll(string(m[2]), `""`, `"`)
+		}
+	}
+	return values
+}
+
+// parseMetaTimestamp converts the meta.cpp timestamp to Unix seconds.
+// DayZ writes a .NET DateTime.ToBinary value, older tools write Unix time.
+func parseMetaTimestamp(raw string) int64 {
+	v, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return 0
+	}
+	ticks := int64(v & dotnetTicksMask)
+	if ticks < dotnetUnixEpochTicks {
+		return int64(v)
+	}
+	return (ticks - dotnetUnixEpochTicks) / dotnetTicksPerSecond
+}
+
+// CheckFreshness decides whether an installed mod is behind the Workshop.
+// installedAt is the timestamp Steam reports for the installed copy (0 if
+// unknown). Steam can stamp an update it never finished writing, so
+// meta.cpp is checked as well whenever it has a timestamp; either one being
+// older than updatedAt makes the mod outdated.
+func CheckFreshness(modID, modPath string, installedAt, updatedAt int64) *ModFreshness {
+	res := &ModFreshness{ModID: modID, InstalledAt: installedAt, UpdatedAt: updatedAt, Source: "none"}
+
+	meta, _ := ReadModMeta(modPath)
+	var metaAt int64
+	if meta != nil {
+		res.Name = meta.Name
+		metaAt = meta.Timestamp
+		if id := meta.PublishedID; id != "" && id != "0" && id != modID {
+			res.WrongMod = true
+			res.Outdated = true
+		}
+	}
+
+	if updatedAt == 0 {
+		return res
+	}
+
+	steamOutdated := installedAt > 0 && installedAt < updatedAt
+	metaOutdated := metaAt > 0 && metaAt+int64(metaTimestampSlack/time.Second) < updatedAt
+	switch {
+	case installedAt > 0 && (steamOutdated || !metaOutdated):
+		res.Source = "steam"
+	case metaAt > 0:
+		res.Source = "meta"
+		res.InstalledAt = metaAt
+	}
+	res.Outdated = res.Outdated || steamOutdated || metaOutdated
+	return res
+}
@@ -0,0 +1,71 @@
+package dayz
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/woozymasta/a2s/pkg/a2s"
+)
+
+// DayZ's default query port (27016) sits this far above the default game port (2302)
+const defaultQueryPortOffset = 24714
+
+// CandidateQueryPorts lists likely query ports for a game port, most
+// common hosting layouts first
+func CandidateQueryPorts(gamePort int) []int {
+	candidates := []int{gamePort + 1, 27016, gamePort + defaultQueryPortOffset, gamePort}
+	seen := make(map[int]bool, len(candidates))
+	ports := make([]int, 0, len(candidates))
+	for _, p := range candidates {
+		if p <= 0 || p > 65535 || seen[p] {
+			continue
+		}
+		seen[p] = true
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+// ProbeQueryPort finds the query port of the server playing on ip:gamePort.
+// All candidates are tried at once; a server that reports the same game
+// port wins over one that merely answers.
+func ProbeQueryPort(ip string, gamePort int, timeout time.Duration) (int, error) {
+	ports := CandidateQueryPorts(gamePort)
+	answered := make([]bool, len(ports))
+	matched := make([]bool, len(ports))
+
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		wg.Add(1)
+		go func(i, port int) {
+			defer wg.Done()
+			client, err := a2s.NewWithString(fmt.Sprintf("%s:%d", ip, port))
+			if err != nil {
+				return
+			}
+			defer client.Close()
+			client.Timeout = timeout
+
+			info, err := client.GetInfo()
+			if err != nil {
+				return
+			}
+			answered[i] = true
+			matched[i] = int(info.Port) == gamePort
+		}(i, port)
+	}
+	wg.Wait()
+
+	for i, port := range ports {
+		if matched[i] {
+			return port, nil
+		}
+	}
+	for i, port := range ports {
+		if answered[i] {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no query port answered for %s:%d", ip, gamePort)
+}
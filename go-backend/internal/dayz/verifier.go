@@ -13,22 +13,28 @@ import (
 
 // VerificationResult represents the result of a server scan
 type VerificationResult struct {
-	Success     bool   `json:"success"`
-	Name        string `json:"name,omitempty"`
-	IP          string `json:"ip,omitempty"`
-	GamePort    int    `json:"gamePort,omitempty"`
-	QueryPort   int    `json:"queryPort,omitempty"`
-	Mods        []Mod  `json:"mods,omitempty"`
-	Version     string `json:"version,omitempty"`
-	Description string `json:"description,omitempty"`
-	Discord     string `json:"discord,omitempty"`
-	Error       string `json:"error,omitempty"`
+	Success     bool       `json:"success"`
+	Name        string     `json:"name,omitempty"`
+	Map         string     `json:"map,omitempty"`
+	IP          string     `json:"ip,omitempty"`
+	GamePort    int        `json:"gamePort,omitempty"`
+	QueryPort   int        `json:"queryPort,omitempty"`
+	Mods        []Mod      `json:"mods,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Discord     string     `json:"discord,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Blocked     bool       `json:"blocked,omitempty"`     // A required mod or DLC is missing
+	BlockReason string     `json:"blockReason,omitempty"` // Why the join is impossible
+	RequiredDLC *DLCStatus `json:"requiredDlc,omitempty"` // DLC the map needs
 }
 
 // Mod represents a single mod
 type Mod struct {
-	Name       string `json:"name"`
-	WorkshopID string `json:"workshopId"`
+	Name         string `json:"name"`
+	WorkshopID   string `json:"workshopId"`
+	Status       string `json:"status,omitempty"`       // Workshop item status (ok, removed, hidden, banned, wrong_app)
+	StatusReason string `json:"statusReason,omitempty"` // Explanation when the mod is unusable
 }
 
 // VerifyMods queries a DayZ server for its detailed information including mods and game port
@@ -53,7 +59,7 @@ func VerifyMods(ip string, port int, timeoutSeconds int) *VerificationResult {
 
 	// 2. Query Rules using A3SB (Wait for A2S to complete first to avoid conflict on single socket if shared, though new client manages it)
 	a3sbClient := &a3sb.Client{Client: client}
-	rules, err := a3sbClient.GetRules(221100)
+	rules, err := a3sbClient.GetRules(AppID)
 	if err != nil {
 		return &VerificationResult{Success: false, Error: fmt.Sprintf("Error querying rules: %v", err)}
 	}
@@ -92,6 +98,7 @@ func VerifyMods(ip string, port int, timeoutSeconds int) *VerificationResult {
 	return &VerificationResult{
 		Success:     true,
 		Name:        info.Name,
+		Map:         info.Map,
 		IP:          ip,
 		GamePort:    int(info.Port),
 		QueryPort:   port,
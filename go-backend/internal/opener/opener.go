@@ -0,0 +1,30 @@
+// Package opener hands URLs and folders to the desktop: the default
+// browser, Steam for steam:// links, or the file manager for paths.
+package opener
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Open opens target, a URL or an existing file or folder, with the
+// system's default handler. It returns once the handler has started.
+func Open(target string) error {
+	if target == "" {
+		return fmt.Errorf("nothing to open")
+	}
+	if !isURL(target) {
+		if _, err := os.Stat(target); err != nil {
+			return err
+		}
+	}
+	return open(target)
+}
+
+// isURL reports whether target has a scheme, e.g. https: or steam:. Drive
+// letters (C:\...) are paths.
+func isURL(target string) bool {
+	i := strings.Index(target, ":")
+	return i > 1 && !strings.ContainsAny(target[:i], `/\`)
+}
@@ -0,0 +1,12 @@
+package opener
+
+import "os/exec"
+
+func open(target string) error {
+	cmd := exec.Command("open", target)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() // reap it
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !windows && !darwin
+
+package opener
+
+import "os/exec"
+
+// open goes through xdg-open, which picks the desktop's handler (and Steam
+// for steam:// if it registered one)
+func open(target string) error {
+	cmd := exec.Command("xdg-open", target)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() // reap it
+	return nil
+}
@@ -0,0 +1,18 @@
+package opener
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// open uses ShellExecute, like explorer does, without a console window
+func open(target string) error {
+	verb, err := windows.UTF16PtrFromString("open")
+	if err != nil {
+		return err
+	}
+	file, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	return windows.ShellExecute(0, verb, file, nil, nil, windows.SW_SHOWNORMAL)
+}
@@ -0,0 +1,63 @@
+package pbo
+
+import (
+	"bufio"
+	"io"
+)
+
+// maxRatio bounds how far a stream can expand: at best a flag byte and 8
+// back-references of 18 bytes each, i.e. 144 bytes out of 17.
+const maxRatio = 9
+
+// decompress expands a BI flavoured LZSS stream of packed bytes into
+// exactly size bytes. Each flag byte describes the next 8 blocks: a set bit
+// is a literal byte, a clear bit is a 2 byte back-reference into the
+// already decoded output. Back-references before the start of the buffer
+// decode as spaces.
+func decompress(r *bufio.Reader, size, packed int) ([]byte, error) {
+	// size comes from the header; don't let a corrupt one pick the allocation
+	if size < 0 || size > packed*maxRatio {
+		return nil, ErrBadSize
+	}
+	out := make([]byte, 0, size)
+
+	for len(out) < size {
+		flags, err := r.ReadByte()
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		for bit := 0; bit < 8 && len(out) < size; bit++ {
+			if flags&(1<<bit) != 0 {
+				b, err := r.ReadByte()
+				if err != nil {
+					return nil, io.ErrUnexpectedEOF
+				}
+				out = append(out, b)
+				continue
+			}
+
+			b1, err1 := r.ReadByte()
+			b2, err2 := r.ReadByte()
+			if err1 != nil || err2 != nil {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			rpos := len(out) - (int(b1) | int(b2&0xF0)<<4)
+			rlen := int(b2&0x0F) + 3
+
+			for ; rlen > 0 && len(out) < size; rlen-- {
+				if rpos < 0 {
+					out = append(out, ' ')
+				} else {
+					out = append(out, out[rpos])
+				}
+				rpos++
+			}
+		}
+	}
+
+	// A 4 byte additive checksum follows the stream; callers rely on the
+	// archive level SHA-1 instead.
+	return out, nil
+}
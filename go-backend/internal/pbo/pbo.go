@@ -0,0 +1,290 @@
+package pbo
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Packing methods found in the entry table
+const (
+	MethodNone       = 0x00000000
+	MethodVersion    = 0x56657273 // "Vers" - header extension with properties
+	MethodCompressed = 0x43707273 // "Cprs" - LZSS compressed entry
+)
+
+const (
+	footerSize = 21   // 0x00 + 20 byte SHA-1
+	maxNameLen = 1024 // sanity limit for entry names
+)
+
+var (
+	ErrNotFound    = errors.New("pbo: file not found in archive")
+	ErrNoChecksum  = errors.New("pbo: missing checksum footer")
+	ErrChecksum    = errors.New("pbo: SHA-1 checksum mismatch")
+	ErrTruncated   = errors.New("pbo: archive is truncated")
+	ErrNameTooLong = errors.New("pbo: entry name too long")
+	ErrBadSize     = errors.New("pbo: entry size doesn't match its data")
+)
+
+// Entry is a single file record from the PBO header
+type Entry struct {
+	Name         string `json:"name"`
+	Method       uint32 `json:"method"`
+	OriginalSize uint32 `json:"originalSize"`
+	Timestamp    uint32 `json:"timestamp"`
+	DataSize     uint32 `json:"dataSize"`
+	Offset       int64  `json:"offset"` // absolute offset of the entry data
+}
+
+// Compressed reports whether the entry data is LZSS packed
+func (e *Entry) Compressed() bool {
+	return e.Method == MethodCompressed && e.OriginalSize != e.DataSize
+}
+
+// Archive is an open PBO. Only the header table is held in memory,
+// file contents are read on demand from the underlying reader.
+type Archive struct {
+	Properties map[string]string
+	Entries    []Entry
+	HeaderSize int64
+	DataSize   int64
+
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+}
+
+// Open opens a PBO file from disk
+func Open(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	a, err := NewReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	a.closer = f
+	return a, nil
+}
+
+// NewReader parses the header of a PBO held in r
+func NewReader(r io.ReaderAt, size int64) (*Archive, error) {
+	a := &Archive{
+		Properties: map[string]string{},
+		r:          r,
+		size:       size,
+	}
+
+	cr := &countingReader{r: bufio.NewReader(io.NewSectionReader(r, 0, size))}
+	if err := a.readHeader(cr); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrTruncated
+		}
+		return nil, err
+	}
+
+	if a.HeaderSize+a.DataSize > size {
+		return nil, ErrTruncated
+	}
+	return a, nil
+}
+
+// Close releases the underlying file if the archive was opened with Open
+func (a *Archive) Close() error {
+	if a.closer != nil {
+		return a.closer.Close()
+	}
+	return nil
+}
+
+// Prefix returns the virtual path prefix of the archive (e.g. "CF\Scripts")
+func (a *Archive) Prefix() string {
+	return a.Properties["prefix"]
+}
+
+// Product returns the product property, "dayz ugc" for most DayZ mods
+func (a *Archive) Product() string {
+	return a.Properties["product"]
+}
+
+// Find looks up an entry by path. Matching is case-insensitive and
+// accepts either slash style.
+func (a *Archive) Find(name string) *Entry {
+	want := normalizeName(name)
+	for i := range a.Entries {
+		if normalizeName(a.Entries[i].Name) == want {
+			return &a.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Open returns a reader over the contents of a single file in the archive
+func (a *Archive) Open(name string) (io.Reader, error) {
+	e := a.Find(name)
+	if e == nil {
+		return nil, ErrNotFound
+	}
+	data := io.NewSectionReader(a.r, e.Offset, int64(e.DataSize))
+	if !e.Compressed() {
+		return data, nil
+	}
+	out, err := decompress(bufio.NewReader(data), int(e.OriginalSize), int(e.DataSize))
+	if err != nil {
+		return nil, fmt.Errorf("pbo: %s: %w", e.Name, err)
+	}
+	return bytes.NewReader(out), nil
+}
+
+// ReadFile extracts a single file, e.g. "config.cpp" or "mod.cpp"
+func (a *Archive) ReadFile(name string) ([]byte, error) {
+	r, err := a.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// Checksum returns the SHA-1 stored in the archive footer
+func (a *Archive) Checksum() ([]byte, error) {
+	end := a.HeaderSize + a.DataSize
+	if a.size-end < footerSize {
+		return nil, ErrNoChecksum
+	}
+	footer := make([]byte, footerSize)
+	if _, err := a.r.ReadAt(footer, a.size-footerSize); err != nil {
+		return nil, ErrNoChecksum
+	}
+	if footer[0] != 0 {
+		return nil, ErrNoChecksum
+	}
+	return footer[1:], nil
+}
+
+// Verify streams the archive through SHA-1 and compares it with the footer
+func (a *Archive) Verify() error {
+	stored, err := a.Checksum()
+	if err != nil {
+		return err
+	}
+	hash := sha1.New()
+	if _, err := io.Copy(hash, io.NewSectionReader(a.r, 0, a.size-footerSize)); err != nil {
+		return err
+	}
+	if !bytes.Equal(stored, hash.Sum(nil)) {
+		return ErrChecksum
+	}
+	return nil
+}
+
+func (a *Archive) readHeader(r *countingReader) error {
+	fields := make([]byte, 20)
+
+	for i := 0; ; i++ {
+		name, err := r.readString()
+		if err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, fields); err != nil {
+			return err
+		}
+
+		e := Entry{
+			Name:         name,
+			Method:       binary.LittleEndian.Uint32(fields[0:4]),
+			OriginalSize: binary.LittleEndian.Uint32(fields[4:8]),
+			Timestamp:    binary.LittleEndian.Uint32(fields[12:16]),
+			DataSize:     binary.LittleEndian.Uint32(fields[16:20]),
+		}
+
+		if name == "" {
+			if i == 0 && e.Method == MethodVersion {
+				if err := a.readProperties(r); err != nil {
+					return err
+				}
+				continue
+			}
+			// Terminating entry
+			break
+		}
+
+		a.Entries = append(a.Entries, e)
+	}
+
+	a.HeaderSize = r.n
+	offset := a.HeaderSize
+	for i := range a.Entries {
+		a.Entries[i].Offset = offset
+		offset += int64(a.Entries[i].DataSize)
+	}
+	a.DataSize = offset - a.HeaderSize
+	return nil
+}
+
+// readProperties reads key/value pairs terminated by an empty string
+func (a *Archive) readProperties(r *countingReader) error {
+	for {
+		key, err := r.readString()
+		if err != nil {
+			return err
+		}
+		if key == "" {
+			return nil
+		}
+		val, err := r.readString()
+		if err != nil {
+			return err
+		}
+		a.Properties[strings.ToLower(key)] = val
+	}
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "/", `\`))
+}
+
+// countingReader tracks how many bytes of the header have been consumed
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readString reads a null terminated string, bailing out early on garbage
+// so a corrupt archive can't make us buffer the whole file.
+func (c *countingReader) readString() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		c.n++
+		if b == 0 {
+			return sb.String(), nil
+		}
+		if sb.Len() >= maxNameLen {
+			return "", ErrNameTooLong
+		}
+		sb.WriteByte(b)
+	}
+}
@@ -0,0 +1,172 @@
+package pbo
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fixtureFile struct {
+	name   string
+	method uint32
+	size   uint32 // original size, for compressed entries
+	data   []byte
+}
+
+// buildPBO lays out a PBO the way the game's tools do: a "Vers" header
+// entry with properties, the entry table, the data and a SHA-1 footer
+func buildPBO(props [][2]string, files []fixtureFile) []byte {
+	var b bytes.Buffer
+	entry := func(name string, method, orig, size uint32) {
+		b.WriteString(name)
+		b.WriteByte(0)
+		for _, v := range []uint32{method, orig, 0, 1700000000, size} {
+			binary.Write(&b, binary.LittleEndian, v)
+		}
+	}
+
+	entry("", MethodVersion, 0, 0)
+	for _, p := range props {
+		b.WriteString(p[0])
+		b.WriteByte(0)
+		b.WriteString(p[1])
+		b.WriteByte(0)
+	}
+	b.WriteByte(0)
+	for _, f := range files {
+		entry(f.name, f.method, f.size, uint32(len(f.data)))
+	}
+	entry("", MethodNone, 0, 0)
+	for _, f := range files {
+		b.Write(f.data)
+	}
+
+	sum := sha1.Sum(b.Bytes())
+	b.WriteByte(0)
+	b.Write(sum[:])
+	return b.Bytes()
+}
+
+func writeFixture(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fixture.pbo")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// "abcabcabcX": three literals, a back-reference 3 bytes back of length 6,
+// then one more literal
+var compressedFixture = []byte{0x17, 'a', 'b', 'c', 3, 3, 'X', 0, 0, 0, 0}
+
+func fixturePBO() []byte {
+	return buildPBO(
+		[][2]string{{"product", "dayz ugc"}, {"Prefix", `CF\Scripts`}},
+		[]fixtureFile{
+			{name: "config.cpp", data: []byte("class CfgPatches {};")},
+			{name: `scripts\mod.c`, method: MethodCompressed, size: 10, data: compressedFixture},
+		},
+	)
+}
+
+func TestOpen(t *testing.T) {
+	a, err := Open(writeFixture(t, fixturePBO()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	if got := a.Prefix(); got != `CF\Scripts` {
+		t.Errorf("Prefix() = %q", got)
+	}
+	if got := a.Product(); got != "dayz ugc" {
+		t.Errorf("Product() = %q", got)
+	}
+	if len(a.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(a.Entries))
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"config.cpp", "class CfgPatches {};"},
+		{"CONFIG.CPP", "class CfgPatches {};"},
+		{"scripts/mod.c", "abcabcabcX"},
+	}
+	for _, tt := range tests {
+		got, err := a.ReadFile(tt.name)
+		if err != nil {
+			t.Errorf("ReadFile(%q): %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("ReadFile(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := a.Open("missing.cpp"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	good := fixturePBO()
+
+	corrupt := append([]byte(nil), good...)
+	corrupt[len(corrupt)-footerSize-1] ^= 0xFF // last data byte
+
+	noFooter := buildPBO(nil, []fixtureFile{{name: "config.cpp", data: []byte("x")}})
+	noFooter = noFooter[:len(noFooter)-footerSize]
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"valid", good, nil},
+		{"data changed", corrupt, ErrChecksum},
+		{"no footer", noFooter, ErrNoChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Open(writeFixture(t, tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer a.Close()
+			if err := a.Verify(); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenTruncated(t *testing.T) {
+	data := fixturePBO()
+	for _, n := range []int{0, 10, 40, len(data) - footerSize - 5} {
+		_, err := Open(writeFixture(t, data[:n]))
+		if !errors.Is(err, ErrTruncated) {
+			t.Errorf("Open(%d of %d bytes) error = %v, want ErrTruncated", n, len(data), err)
+		}
+	}
+}
+
+func TestOversizedEntry(t *testing.T) {
+	data := buildPBO(nil, []fixtureFile{
+		{name: "bomb.c", method: MethodCompressed, size: 0xFFFFFFF0, data: compressedFixture},
+	})
+	a, err := Open(writeFixture(t, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	if _, err := a.ReadFile("bomb.c"); !errors.Is(err, ErrBadSize) {
+		t.Errorf("ReadFile() error = %v, want ErrBadSize", err)
+	}
+}
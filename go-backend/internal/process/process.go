@@ -0,0 +1,14 @@
+// Package process looks for running programs by executable name, for
+// following a game that was started through a launcher or Steam.
+package process
+
+import "errors"
+
+// ErrUnsupported means processes can't be listed on this platform
+var ErrUnsupported = errors.New("process listing not supported on this platform")
+
+// Running reports whether a process with this executable name (e.g.
+// "DayZ_x64.exe") is running. Matching is case-insensitive.
+func Running(exeName string) (bool, error) {
+	return running(exeName)
+}
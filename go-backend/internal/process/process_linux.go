@@ -0,0 +1,34 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The kernel truncates a process name to this many bytes
+const maxCommLen = 15
+
+// running scans /proc. Games under Wine/Proton keep their .exe name.
+func running(exeName string) (bool, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return false, err
+	}
+	if len(exeName) > maxCommLen {
+		exeName = exeName[:maxCommLen]
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.Trim(e.Name(), "0123456789") != "" {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join("/proc", e.Name(), "comm"))
+		if err != nil {
+			continue // exited meanwhile
+		}
+		if strings.EqualFold(strings.TrimSpace(string(comm)), exeName) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,7 @@
+//go:build !windows && !linux
+
+package process
+
+func running(exeName string) (bool, error) {
+	return false, ErrUnsupported
+}
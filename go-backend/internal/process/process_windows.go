@@ -0,0 +1,28 @@
+package process
+
+import (
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func running(exeName string) (bool, error) {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return false, err
+	}
+	defer windows.CloseHandle(snap)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		if strings.EqualFold(windows.UTF16ToString(entry.ExeFile[:]), exeName) {
+			return true, nil
+		}
+	}
+	if err == windows.ERROR_NO_MORE_FILES {
+		return false, nil
+	}
+	return false, err
+}
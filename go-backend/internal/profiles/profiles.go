@@ -0,0 +1,208 @@
+// Package profiles stores launcher settings per Steam account, so people
+// sharing a PC each keep their own survivor name, launch parameters,
+// favourite servers and presets.
+package profiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"dayz-launcher-go/internal/steamid"
+)
+
+// DefaultID is the profile used while no Steam account is known (Steam
+// closed, or before login). Settings from before profiles existed are
+// migrated from here too.
+const DefaultID steamid.ID = 0
+
+// Preset is a saved combination of launch settings and mods
+type Preset struct {
+	Name         string   `json:"name"`
+	LaunchParams string   `json:"launchParams,omitempty"`
+	Mods         []string `json:"mods,omitempty"`
+}
+
+// Profile is everything the launcher remembers for one account. Settings
+// holds frontend options that have no field of their own.
+type Profile struct {
+	SteamID      steamid.ID                 `json:"steamId"`
+	PersonaName  string                     `json:"personaName,omitempty"`
+	SurvivorName string                     `json:"survivorName,omitempty"`
+	LaunchParams string                     `json:"launchParams,omitempty"`
+	Favourites   []string                   `json:"favourites"` // "ip:queryPort"
+	Presets      []Preset                   `json:"presets"`
+	Settings     map[string]json.RawMessage `json:"settings,omitempty"`
+	Migrated     bool                       `json:"migrated,omitempty"`
+	UpdatedAt    time.Time                  `json:"updatedAt"`
+}
+
+// Empty reports whether nothing has been set yet
+func (p *Profile) Empty() bool {
+	return p.SurvivorName == "" && p.LaunchParams == "" && len(p.Favourites) == 0 &&
+		len(p.Presets) == 0 && len(p.Settings) == 0
+}
+
+// Merge copies src's settings into p without overwriting anything p has:
+// empty fields are filled, favourites are unioned and presets with new
+// names are appended
+func (p *Profile) Merge(src *Profile) {
+	if p.SurvivorName == "" {
+		p.SurvivorName = src.SurvivorName
+	}
+	if p.LaunchParams == "" {
+		p.LaunchParams = src.LaunchParams
+	}
+
+	seen := make(map[string]bool, len(p.Favourites))
+	for _, f := range p.Favourites {
+		seen[f] = true
+	}
+	for _, f := range src.Favourites {
+		if !seen[f] {
+			seen[f] = true
+			p.Favourites = append(p.Favourites, f)
+		}
+	}
+
+	names := make(map[string]bool, len(p.Presets))
+	for _, pr := range p.Presets {
+		names[strings.ToLower(pr.Name)] = true
+	}
+	for _, pr := range src.Presets {
+		if !names[strings.ToLower(pr.Name)] {
+			names[strings.ToLower(pr.Name)] = true
+			p.Presets = append(p.Presets, pr)
+		}
+	}
+
+	for k, v := range src.Settings {
+		if _, ok := p.Settings[k]; !ok {
+			if p.Settings == nil {
+				p.Settings = make(map[string]json.RawMessage)
+			}
+			p.Settings[k] = v
+		}
+	}
+}
+
+// Summary describes a stored profile without its settings
+type Summary struct {
+	SteamID     steamid.ID `json:"steamId"`
+	PersonaName string     `json:"personaName,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Store keeps one JSON file per account in a directory
+type Store struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewStore uses dir, which is created on first save
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(id steamid.ID) string {
+	if id == DefaultID {
+		return filepath.Join(s.dir, "default.json")
+	}
+	return filepath.Join(s.dir, id.String()+".json")
+}
+
+// Exists reports whether id has a saved profile
+func (s *Store) Exists(id steamid.ID) bool {
+	_, err := os.Stat(s.path(id))
+	return err == nil
+}
+
+// Load reads id's profile. A missing profile is not an error: a fresh one
+// is returned (and not saved until Save).
+func (s *Store) Load(id steamid.ID) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &Profile{SteamID: id}
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return normalize(p), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", id, err)
+	}
+	p.SteamID = id // the file name wins over whatever the file says
+	return normalize(p), nil
+}
+
+// Save writes p under p.SteamID, replacing the file atomically
+func (s *Store) Save(p *Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	p.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(normalize(p), "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.path(p.SteamID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns the saved account profiles, most recently used first. The
+// default profile is left out.
+func (s *Store) List() ([]Summary, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Summary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Summary{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		id, err := steamid.Parse(name)
+		if err != nil {
+			continue
+		}
+		p, err := s.Load(id)
+		if err != nil {
+			continue
+		}
+		list = append(list, Summary{SteamID: id, PersonaName: p.PersonaName, UpdatedAt: p.UpdatedAt})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UpdatedAt.After(list[j].UpdatedAt) })
+	return list, nil
+}
+
+// normalize replaces nil slices so the frontend always gets arrays
+func normalize(p *Profile) *Profile {
+	if p.Favourites == nil {
+		p.Favourites = []string{}
+	}
+	if p.Presets == nil {
+		p.Presets = []Preset{}
+	}
+	return p
+}
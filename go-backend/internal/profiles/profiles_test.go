@@ -0,0 +1,105 @@
+package profiles
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"dayz-launcher-go/internal/steamid"
+)
+
+const testID steamid.ID = 76561197960287930
+
+func TestStoreRoundTrip(t *testing.T) {
+	for _, id := range []steamid.ID{DefaultID, testID} {
+		s := NewStore(filepath.Join(t.TempDir(), "profiles"))
+		if s.Exists(id) {
+			t.Fatalf("%s exists before saving", id)
+		}
+
+		p := &Profile{
+			SteamID:      id,
+			SurvivorName: "Survivor",
+			LaunchParams: "-nosplash",
+			Favourites:   []string{"1.2.3.4:27016"},
+			Presets:      []Preset{{Name: "PvP", Mods: []string{"1559212036"}}},
+			Settings:     map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)},
+		}
+		if err := s.Save(p); err != nil {
+			t.Fatalf("Save(%s): %v", id, err)
+		}
+		if !s.Exists(id) {
+			t.Fatalf("%s doesn't exist after saving", id)
+		}
+
+		got, err := s.Load(id)
+		if err != nil {
+			t.Fatalf("Load(%s): %v", id, err)
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("Load(%s) = %+v, want %+v", id, got, p)
+		}
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "profiles"))
+	p, err := s.Load(testID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SteamID != testID || !p.Empty() || p.Favourites == nil || p.Presets == nil {
+		t.Errorf("Load(missing) = %+v", p)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := &Profile{
+		SurvivorName: "Mine",
+		Favourites:   []string{"1.2.3.4:27016"},
+		Presets:      []Preset{{Name: "PvP", LaunchParams: "-mine"}},
+	}
+	p.Merge(&Profile{
+		SurvivorName: "Theirs",
+		LaunchParams: "-nosplash",
+		Favourites:   []string{"1.2.3.4:27016", "5.6.7.8:27016"},
+		Presets:      []Preset{{Name: "pvp", LaunchParams: "-theirs"}, {Name: "PvE"}},
+		Settings:     map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)},
+	})
+
+	want := &Profile{
+		SurvivorName: "Mine",
+		LaunchParams: "-nosplash",
+		Favourites:   []string{"1.2.3.4:27016", "5.6.7.8:27016"},
+		Presets:      []Preset{{Name: "PvP", LaunchParams: "-mine"}, {Name: "PvE"}},
+		Settings:     map[string]json.RawMessage{"theme": json.RawMessage(`"dark"`)},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("Merge() = %+v, want %+v", p, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "profiles"))
+	if list, err := s.List(); err != nil || len(list) != 0 {
+		t.Fatalf("List() on empty store = %v, %v", list, err)
+	}
+
+	older, newer := testID, steamid.FromAccountID(1)
+	for _, id := range []steamid.ID{DefaultID, older, newer} {
+		if err := s.Save(&Profile{SteamID: id, PersonaName: id.String()}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond) // distinct UpdatedAt on coarse clocks
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].SteamID != newer || list[1].SteamID != older {
+		t.Errorf("List() = %+v, want %s then %s without the default", list, newer, older)
+	}
+}
@@ -0,0 +1,278 @@
+// Package steamid parses and formats SteamIDs. IDs travel to the frontend
+// as strings since JavaScript numbers can't hold 64 bits.
+package steamid
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ID is a SteamID64: account (32 bits), instance (20), type (4), universe (8)
+type ID uint64
+
+// Universe is EUniverse
+type Universe uint8
+
+const (
+	UniverseInvalid  Universe = 0
+	UniversePublic   Universe = 1
+	UniverseBeta     Universe = 2
+	UniverseInternal Universe = 3
+	UniverseDev      Universe = 4
+)
+
+// AccountType is EAccountType
+type AccountType uint8
+
+const (
+	TypeInvalid        AccountType = 0
+	TypeIndividual     AccountType = 1
+	TypeMultiseat      AccountType = 2
+	TypeGameServer     AccountType = 3
+	TypeAnonGameServer AccountType = 4
+	TypePending        AccountType = 5
+	TypeContentServer  AccountType = 6
+	TypeClan           AccountType = 7
+	TypeChat           AccountType = 8
+	TypeConsoleUser    AccountType = 9
+	TypeAnonUser       AccountType = 10
+)
+
+// Instance used by individual accounts on the desktop client
+const desktopInstance = 1
+
+var (
+	ErrInvalid = errors.New("invalid SteamID")
+	// ErrVanityURL means the profile URL uses a custom name (/id/...),
+	// which only the Steam Web API can resolve
+	ErrVanityURL = errors.New("custom profile URLs can't be resolved offline")
+)
+
+// Steam3 type letters, indexed by AccountType
+var typeLetters = []byte{'I', 'U', 'M', 'G', 'A', 'P', 'C', 'g', 'T', '?', 'a'}
+
+var (
+	steam2Pattern = regexp.MustCompile(`^STEAM_([0-5]):([01]):(\d{1,10})$`)
+	steam3Pattern = regexp.MustCompile(`^\[?([IUMGAPCgTLca]):([0-5]):(\d{1,10})(?::(\d{1,7}))?\]?$`)
+)
+
+// New assembles an ID from its parts
+func New(universe Universe, typ AccountType, instance uint32, account uint32) ID {
+	return ID(uint64(universe)<<56 | uint64(typ&0xF)<<52 | uint64(instance&0xFFFFF)<<32 | uint64(account))
+}
+
+// FromAccountID returns the public individual account with this account ID
+func FromAccountID(account uint32) ID {
+	return New(UniversePublic, TypeIndividual, desktopInstance, account)
+}
+
+func (id ID) AccountID() uint32  { return uint32(id) }
+func (id ID) Instance() uint32   { return uint32(id>>32) & 0xFFFFF }
+func (id ID) Type() AccountType  { return AccountType(id>>52) & 0xF }
+func (id ID) Universe() Universe { return Universe(id >> 56) }
+func (id ID) IsIndividual() bool { return id.Type() == TypeIndividual }
+func (id ID) Uint64() uint64     { return uint64(id) }
+
+// Valid checks the universe and account type, and the rules Steam applies
+// to them (e.g. an individual needs a non-zero account ID)
+func (id ID) Valid() bool {
+	if id.Universe() == UniverseInvalid || id.Universe() > UniverseDev {
+		return false
+	}
+	switch t := id.Type(); t {
+	case TypeIndividual:
+		return id.AccountID() != 0 && id.Instance() <= 4
+	case TypeClan:
+		return id.AccountID() != 0 && id.Instance() == 0
+	case TypeGameServer:
+		return id.AccountID() != 0
+	case TypeInvalid:
+		return false
+	default:
+		return t <= TypeAnonUser
+	}
+}
+
+// String is the decimal SteamID64
+func (id ID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// Steam2 renders STEAM_X:Y:Z. Only meaningful for individual accounts.
+func (id ID) Steam2() string {
+	universe := id.Universe()
+	if universe == UniversePublic {
+		universe = 0 // legacy format writes public as 0
+	}
+	return fmt.Sprintf("STEAM_%d:%d:%d", universe, id.AccountID()&1, id.AccountID()>>1)
+}
+
+// Steam3 renders [U:1:account]
+func (id ID) Steam3() string {
+	letter := byte('I')
+	if t := int(id.Type()); t < len(typeLetters) {
+		letter = typeLetters[t]
+	}
+	if id.Type() == TypeIndividual && id.Instance() != desktopInstance {
+		return fmt.Sprintf("[%c:%d:%d:%d]", letter, id.Universe(), id.AccountID(), id.Instance())
+	}
+	return fmt.Sprintf("[%c:%d:%d]", letter, id.Universe(), id.AccountID())
+}
+
+// ProfileURL is the community profile link
+func (id ID) ProfileURL() string {
+	return "https://steamcommunity.com/profiles/" + id.String()
+}
+
+// Parse accepts a SteamID64, Steam2 (STEAM_0:1:123), Steam3 ([U:1:123])
+// or a steamcommunity.com/profiles/ URL. The result is always Valid.
+func Parse(s string) (ID, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalid
+	}
+
+	var id ID
+	var err error
+	switch {
+	case strings.Contains(s, "steamcommunity.com/"):
+		id, err = parseProfileURL(s)
+	case strings.HasPrefix(s, "STEAM_"):
+		id, err = parseSteam2(s)
+	case steam3Pattern.MatchString(s):
+		id, err = parseSteam3(s)
+	default:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, 64)
+		id = ID(n)
+	}
+	if err != nil {
+		if errors.Is(err, ErrVanityURL) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	if !id.Valid() {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return id, nil
+}
+
+func parseSteam2(s string) (ID, error) {
+	m := steam2Pattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, ErrInvalid
+	}
+	universe, _ := strconv.Atoi(m[1])
+	if universe == 0 {
+		universe = int(UniversePublic)
+	}
+	y, _ := strconv.ParseUint(m[2], 10, 32)
+	z, err := strconv.ParseUint(m[3], 10, 31)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return New(Universe(universe), TypeIndividual, desktopInstance, uint32(z<<1|y)), nil
+}
+
+func parseSteam3(s string) (ID, error) {
+	m := steam3Pattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, ErrInvalid
+	}
+	universe, _ := strconv.Atoi(m[2])
+	account, err := strconv.ParseUint(m[3], 10, 32)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+
+	var typ AccountType
+	var instance uint32
+	switch letter := m[1][0]; letter {
+	case 'U':
+		typ, instance = TypeIndividual, desktopInstance
+	case 'T', 'L', 'c':
+		// Chat IDs; L (lobby) and c (clan chat) set instance flags
+		typ = TypeChat
+		switch letter {
+		case 'c':
+			instance = 0x80000
+		case 'L':
+			instance = 0x40000
+		}
+	default:
+		typ = AccountType(strings.IndexByte(string(typeLetters), letter))
+	}
+	if m[4] != "" {
+		n, err := strconv.ParseUint(m[4], 10, 20)
+		if err != nil {
+			return 0, ErrInvalid
+		}
+		instance = uint32(n)
+	}
+	return New(Universe(universe), typ, instance, uint32(account)), nil
+}
+
+func parseProfileURL(s string) (ID, error) {
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return 0, ErrInvalid
+	}
+	switch parts[0] {
+	case "profiles":
+		if steam3Pattern.MatchString(parts[1]) {
+			return parseSteam3(parts[1])
+		}
+		n, err := strconv.ParseUint(parts[1], 10, 64)
+		return ID(n), err
+	case "id":
+		return 0, ErrVanityURL
+	}
+	return 0, ErrInvalid
+}
+
+// MarshalText writes the SteamID64 as a decimal string, which also makes
+// encoding/json emit a JSON string (and allows IDs as map keys). The zero
+// ID, used for "no account", is written as "0".
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText accepts any format Parse does. Empty text and "0" are the
+// zero ID, so it round-trips even though Parse rejects it.
+func (id *ID) UnmarshalText(text []byte) error {
+	if len(text) == 0 || string(text) == "0" {
+		*id = 0
+		return nil
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// UnmarshalJSON takes a string in any Parse format, or a bare number for
+// callers that (lossily) sent one. null leaves the ID unchanged.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unq, err := strconv.Unquote(s); err == nil {
+		return id.UnmarshalText([]byte(unq))
+	}
+	return id.UnmarshalText(data)
+}
@@ -0,0 +1,179 @@
+package steamid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// gaben is [U:1:22202], a public individual on the desktop instance
+const gaben ID = 76561197960287930
+
+func TestParts(t *testing.T) {
+	if got := FromAccountID(22202); got != gaben {
+		t.Errorf("FromAccountID() = %d, want %d", got, gaben)
+	}
+	if gaben.AccountID() != 22202 || gaben.Instance() != 1 || gaben.Type() != TypeIndividual || gaben.Universe() != UniversePublic {
+		t.Errorf("parts = %d/%d/%d/%d", gaben.AccountID(), gaben.Instance(), gaben.Type(), gaben.Universe())
+	}
+	if got := gaben.ProfileURL(); got != "https://steamcommunity.com/profiles/76561197960287930" {
+		t.Errorf("ProfileURL() = %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ID
+	}{
+		{"76561197960287930", gaben},
+		{"  76561197960287930\n", gaben},
+
+		// Steam2: Y is the low account bit, Z the rest; universe 0 is public
+		{"STEAM_0:0:11101", gaben},
+		{"STEAM_1:0:11101", gaben},
+		{"STEAM_0:1:11101", FromAccountID(22203)},
+
+		// Steam3, with and without brackets and instance
+		{"[U:1:22202]", gaben},
+		{"U:1:22202", gaben},
+		{"[U:1:22202:4]", New(UniversePublic, TypeIndividual, 4, 22202)},
+		{"[g:1:4]", New(UniversePublic, TypeClan, 0, 4)},
+		{"[G:1:7]", New(UniversePublic, TypeGameServer, 0, 7)},
+		{"[T:1:5]", New(UniversePublic, TypeChat, 0, 5)},
+		{"[c:1:5]", New(UniversePublic, TypeChat, 0x80000, 5)},
+		{"[L:1:5]", New(UniversePublic, TypeChat, 0x40000, 5)},
+
+		// Profile URLs
+		{"https://steamcommunity.com/profiles/76561197960287930/", gaben},
+		{"http://steamcommunity.com/profiles/76561197960287930?l=english", gaben},
+		{"steamcommunity.com/profiles/[U:1:22202]", gaben},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"", ErrInvalid},
+		{"0", ErrInvalid},
+		{"123", ErrInvalid}, // universe 0
+		{"abc", ErrInvalid},
+		{"-76561197960287930", ErrInvalid},
+		{"STEAM_0:2:1", ErrInvalid},
+		{"STEAM_6:0:1", ErrInvalid},
+		{"STEAM_0:0:4294967295", ErrInvalid},
+		{"[U:1:0]", ErrInvalid},
+		{"[U:5:1]", ErrInvalid},
+		{"[U:9:1]", ErrInvalid},
+		{"[I:1:1]", ErrInvalid},
+		{"[g:1:4:1]", ErrInvalid}, // clans have no instance
+		{"https://steamcommunity.com/groups/dayz", ErrInvalid},
+		{"https://steamcommunity.com/profiles/", ErrInvalid},
+		{"https://steamcommunity.com/id/gabelogannewell", ErrVanityURL},
+		{"steamcommunity.com/id/gabelogannewell/", ErrVanityURL},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) = %d, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	ids := []ID{
+		gaben,
+		FromAccountID(1),
+		FromAccountID(0xFFFFFFFF),
+		New(UniversePublic, TypeIndividual, 4, 22202),
+		New(UniverseBeta, TypeIndividual, 1, 99),
+		New(UniversePublic, TypeClan, 0, 103582791),
+		New(UniversePublic, TypeGameServer, 0, 7),
+	}
+	for _, id := range ids {
+		formats := []string{id.String(), id.Steam3(), id.ProfileURL()}
+		if id.IsIndividual() && id.Instance() == 1 {
+			formats = append(formats, id.Steam2())
+		}
+		for _, s := range formats {
+			got, err := Parse(s)
+			if err != nil || got != id {
+				t.Errorf("Parse(%q) = %d, %v, want %d", s, got, err, id)
+			}
+		}
+	}
+
+	if got := gaben.Steam2(); got != "STEAM_0:0:11101" {
+		t.Errorf("Steam2() = %q", got)
+	}
+	if got := gaben.Steam3(); got != "[U:1:22202]" {
+		t.Errorf("Steam3() = %q", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type wrapper struct {
+		ID ID `json:"id"`
+	}
+
+	for _, id := range []ID{gaben, 0} {
+		data, err := json.Marshal(wrapper{id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"id":"` + id.String() + `"}`
+		if string(data) != want {
+			t.Errorf("Marshal(%d) = %s, want %s", id, data, want)
+		}
+		var back wrapper
+		if err := json.Unmarshal(data, &back); err != nil || back.ID != id {
+			t.Errorf("Unmarshal(%s) = %d, %v, want %d", data, back.ID, err, id)
+		}
+	}
+
+	data, _ := json.Marshal(map[ID]bool{gaben: true})
+	if string(data) != `{"76561197960287930":true}` {
+		t.Errorf("map key = %s", data)
+	}
+
+	tests := []struct {
+		in   string
+		want ID
+	}{
+		{`{"id":"[U:1:22202]"}`, gaben},
+		{`{"id":"STEAM_0:0:11101"}`, gaben},
+		{`{"id":76561197960287930}`, gaben},
+		{`{"id":""}`, 0},
+		{`{"id":0}`, 0},
+		{`{"id":null}`, 5}, // left as it was
+	}
+	for _, tt := range tests {
+		w := wrapper{ID: 5}
+		if err := json.Unmarshal([]byte(tt.in), &w); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if w.ID != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, w.ID, tt.want)
+		}
+	}
+
+	for _, in := range []string{`{"id":"nope"}`, `{"id":123}`, `{"id":"https://steamcommunity.com/id/x"}`} {
+		var w wrapper
+		if err := json.Unmarshal([]byte(in), &w); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded with %d", in, w.ID)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package steamlocate
+
+import (
+	"strconv"
+
+	"dayz-launcher-go/internal/vdf"
+)
+
+// appManifest is the part of appmanifest_*.acf we care about
+type appManifest struct {
+	AppState struct {
+		InstallDir string `vdf:"installdir"`
+	}
+}
+
+// parseLibraryFolders handles both the current format (numbered blocks with
+// "path" and "apps") and the legacy one (numbered keys holding the path)
+func parseLibraryFolders(data []byte) []Library {
+	root, err := vdf.ParseBytes(data)
+	if err != nil {
+		return nil
+	}
+	top := root.Get("libraryfolders")
+	if top == nil {
+		return nil
+	}
+
+	var libs []Library
+	for _, entry := range top.Children {
+		if _, err := strconv.Atoi(entry.Key); err != nil {
+			continue
+		}
+		if !entry.IsBlock() {
+			if entry.Value != "" {
+				libs = append(libs, Library{Path: entry.Value})
+			}
+			continue
+		}
+		path := entry.String("path")
+		if path == "" {
+			continue
+		}
+		lib := Library{Path: path, Apps: map[uint32]bool{}}
+		if apps := entry.Get("apps"); apps != nil {
+			for _, app := range apps.Children {
+				if id, err := strconv.ParseUint(app.Key, 10, 32); err == nil {
+					lib.Apps[uint32(id)] = true
+				}
+			}
+		}
+		libs = append(libs, lib)
+	}
+	return libs
+}
+
+// parseInstallDir returns AppState.installdir from an appmanifest_*.acf
+func parseInstallDir(data []byte) string {
+	var m appManifest
+	if err := vdf.Unmarshal(data, &m); err != nil {
+		return ""
+	}
+	return m.AppState.InstallDir
+}
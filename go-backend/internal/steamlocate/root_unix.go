@@ -0,0 +1,41 @@
+//go:build !windows
+
+package steamlocate
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// candidateRoots returns possible Steam roots for the current user,
+// covering native, distro (~/.steam symlinks) and Flatpak installs
+func candidateRoots() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return rootsForHome(home)
+}
+
+func rootsForHome(home string) []string {
+	if runtime.GOOS == "darwin" {
+		return []string{filepath.Join(home, "Library", "Application Support", "Steam")}
+	}
+
+	var roots []string
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		roots = append(roots, filepath.Join(xdg, "Steam"))
+	}
+	return append(roots,
+		filepath.Join(home, ".steam", "steam"),
+		filepath.Join(home, ".steam", "root"),
+		filepath.Join(home, ".local", "share", "Steam"),
+		filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", ".local", "share", "Steam"),
+		filepath.Join(home, ".var", "app", "com.valvesoftware.Steam", "data", "Steam"),
+	)
+}
+
+func pathsEqual(a, b string) bool {
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+
+package steamlocate
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRootsForHomeFollowsSymlink(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("macOS has a single Steam root")
+	}
+	t.Setenv("XDG_DATA_HOME", "")
+
+	home := t.TempDir()
+	root := filepath.Join(home, ".local", "share", "Steam")
+	mkdir(t, filepath.Join(root, "steamapps"))
+	mkdir(t, filepath.Join(home, ".steam"))
+	if err := os.Symlink(root, filepath.Join(home, ".steam", "steam")); err != nil {
+		t.Skip(err)
+	}
+	// .steam/steam is found first and lists the real path; it mustn't show up twice
+	writeFile(t, filepath.Join(root, "steamapps", "libraryfolders.vdf"), `"libraryfolders"
+{
+	"0" { "path" "`+root+`" }
+}`)
+
+	var s *Steam
+	for _, r := range rootsForHome(home) {
+		if found, err := Open(r); err == nil {
+			s = found
+			break
+		}
+	}
+	if s == nil {
+		t.Fatal("no root found")
+	}
+	if len(s.Libraries) != 1 {
+		t.Errorf("libraries = %+v, want just the root", s.Libraries)
+	}
+}
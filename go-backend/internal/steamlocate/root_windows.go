@@ -0,0 +1,49 @@
+package steamlocate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// candidateRoots returns possible Steam roots, registry entries first
+func candidateRoots() []string {
+	var roots []string
+
+	keys := []struct {
+		root  registry.Key
+		path  string
+		value string
+	}{
+		{registry.CURRENT_USER, `Software\Valve\Steam`, "SteamPath"},
+		{registry.LOCAL_MACHINE, `SOFTWARE\WOW6432Node\Valve\Steam`, "InstallPath"},
+		{registry.LOCAL_MACHINE, `SOFTWARE\Valve\Steam`, "InstallPath"},
+	}
+	for _, k := range keys {
+		key, err := registry.OpenKey(k.root, k.path, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		val, _, err := key.GetStringValue(k.value)
+		key.Close()
+		if err == nil && val != "" {
+			// SteamPath is stored with forward slashes
+			roots = append(roots, filepath.Clean(val))
+		}
+	}
+
+	// Default install locations if the registry is empty (portable installs)
+	for _, env := range []string{"ProgramFiles(x86)", "ProgramFiles"} {
+		if dir := os.Getenv(env); dir != "" {
+			roots = append(roots, filepath.Join(dir, "Steam"))
+		}
+	}
+	roots = append(roots, `C:\Steam`)
+	return roots
+}
+
+func pathsEqual(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
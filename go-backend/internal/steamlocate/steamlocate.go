@@ -0,0 +1,190 @@
+package steamlocate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"dayz-launcher-go/internal/dayz"
+)
+
+var ErrNotFound = errors.New("steam installation not found")
+
+// Library is one Steam library folder (the Steam root is always the first)
+type Library struct {
+	Path string          `json:"path"`
+	Apps map[uint32]bool `json:"apps,omitempty"` // from libraryfolders.vdf, may be empty on old clients
+}
+
+// Steam is a located Steam installation and its library folders
+type Steam struct {
+	Root      string    `json:"root"`
+	Libraries []Library `json:"libraries"`
+}
+
+// Locate finds the Steam installation for the current user
+func Locate() (*Steam, error) {
+	for _, root := range candidateRoots() {
+		if s, err := Open(root); err == nil {
+			return s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Open reads the Steam installation at root
+func Open(root string) (*Steam, error) {
+	steamApps := steamAppsDir(root)
+	if steamApps == "" {
+		return nil, fmt.Errorf("%s: no steamapps folder", root)
+	}
+
+	s := &Steam{Root: root}
+	s.Libraries = append(s.Libraries, Library{Path: root})
+
+	data, err := os.ReadFile(filepath.Join(steamApps, "libraryfolders.vdf"))
+	if err != nil {
+		// Fresh installs only have the root library
+		return s, nil
+	}
+	for _, lib := range parseLibraryFolders(data) {
+		if samePath(lib.Path, root) {
+			s.Libraries[0].Apps = lib.Apps
+			continue
+		}
+		s.Libraries = append(s.Libraries, lib)
+	}
+	return s, nil
+}
+
+// SteamApps returns the library's steamapps folder, or "" if it's missing
+func (l *Library) SteamApps() string {
+	return steamAppsDir(l.Path)
+}
+
+// HasApp reports whether the app is installed in this library
+func (l *Library) HasApp(appID uint32) bool {
+	if l.Apps[appID] {
+		return true
+	}
+	steamApps := l.SteamApps()
+	if steamApps == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(steamApps, appManifestName(appID)))
+	return err == nil
+}
+
+// FindApp returns the library that holds appID
+func (s *Steam) FindApp(appID uint32) *Library {
+	for i := range s.Libraries {
+		if s.Libraries[i].HasApp(appID) {
+			return &s.Libraries[i]
+		}
+	}
+	return nil
+}
+
+// AppInstallDir returns steamapps/common/<installdir> for an installed app
+func (s *Steam) AppInstallDir(appID uint32) string {
+	lib := s.FindApp(appID)
+	if lib == nil {
+		return ""
+	}
+	steamApps := lib.SteamApps()
+
+	installDir := ""
+	if data, err := os.ReadFile(filepath.Join(steamApps, appManifestName(appID))); err == nil {
+		installDir = parseInstallDir(data)
+	}
+	if installDir == "" && appID == dayz.AppID {
+		installDir = "DayZ"
+	}
+	if installDir == "" {
+		return ""
+	}
+
+	dir := filepath.Join(steamApps, "common", installDir)
+	if _, err := os.Stat(dir); err != nil {
+		return ""
+	}
+	return dir
+}
+
+// WorkshopContentDir returns steamapps/workshop/content/<appID> from the
+// library holding the app. Other libraries are checked as a fallback since
+// Steam keeps workshop content next to the game.
+func (s *Steam) WorkshopContentDir(appID uint32) string {
+	var libs []*Library
+	if lib := s.FindApp(appID); lib != nil {
+		libs = append(libs, lib)
+	}
+	for i := range s.Libraries {
+		libs = append(libs, &s.Libraries[i])
+	}
+
+	for _, lib := range libs {
+		steamApps := lib.SteamApps()
+		if steamApps == "" {
+			continue
+		}
+		dir := filepath.Join(steamApps, "workshop", "content", strconv.FormatUint(uint64(appID), 10))
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// WorkshopDir returns the steamapps/workshop folder that holds the app's
+// appworkshop_<appID>.acf, falling back to the parent of its content dir
+func (s *Steam) WorkshopDir(appID uint32) string {
+	name := fmt.Sprintf("appworkshop_%d.acf", appID)
+	if lib := s.FindApp(appID); lib != nil {
+		if steamApps := lib.SteamApps(); steamApps != "" {
+			if _, err := os.Stat(filepath.Join(steamApps, "workshop", name)); err == nil {
+				return filepath.Join(steamApps, "workshop")
+			}
+		}
+	}
+	for i := range s.Libraries {
+		steamApps := s.Libraries[i].SteamApps()
+		if steamApps == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(steamApps, "workshop", name)); err == nil {
+			return filepath.Join(steamApps, "workshop")
+		}
+	}
+	if content := s.WorkshopContentDir(appID); content != "" {
+		return filepath.Dir(filepath.Dir(content))
+	}
+	return ""
+}
+
+func appManifestName(appID uint32) string {
+	return fmt.Sprintf("appmanifest_%d.acf", appID)
+}
+
+// steamAppsDir handles the legacy "SteamApps" capitalisation on case sensitive filesystems
+func steamAppsDir(root string) string {
+	for _, name := range []string{"steamapps", "SteamApps"} {
+		dir := filepath.Join(root, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// samePath compares two library paths, following symlinks when both exist
+func samePath(a, b string) bool {
+	ca, errA := filepath.EvalSymlinks(a)
+	cb, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return pathsEqual(a, b)
+	}
+	return pathsEqual(ca, cb)
+}
@@ -0,0 +1,205 @@
+package steamlocate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"dayz-launcher-go/internal/dayz"
+)
+
+func TestParseLibraryFolders(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Library
+	}{
+		{
+			name: "current",
+			data: `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"C:\\Program Files (x86)\\Steam"
+		"label"		""
+		"contentid"		"4207519066932658211"
+		"apps"
+		{
+			"228980"		"463493297"
+		}
+	}
+	"1"
+	{
+		"path"		"D:\\SteamLibrary"
+		"apps"
+		{
+			"221100"		"16712482134"
+			"1151700"		"0"
+		}
+	}
+}`,
+			want: []Library{
+				{Path: `C:\Program Files (x86)\Steam`, Apps: map[uint32]bool{228980: true}},
+				{Path: `D:\SteamLibrary`, Apps: map[uint32]bool{221100: true, 1151700: true}},
+			},
+		},
+		{
+			name: "legacy",
+			data: `"LibraryFolders"
+{
+	"TimeNextStatsReport"		"1650000000"
+	"ContentStatsID"		"-4215426101447530000"
+	"1"		"D:\\SteamLibrary"
+	"2"		"/mnt/games/Steam"
+}`,
+			want: []Library{
+				{Path: `D:\SteamLibrary`},
+				{Path: "/mnt/games/Steam"},
+			},
+		},
+		{
+			name: "broken",
+			data: `"libraryfolders" { "0" { "path" "C:\\Steam"`,
+			want: nil,
+		},
+		{
+			name: "wrong root key",
+			data: `"config" { "0" "D:\\SteamLibrary" }`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLibraryFolders([]byte(tt.data))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLibraryFolders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func vdfPath(path string) string {
+	return strings.ReplaceAll(path, `\`, `\\`)
+}
+
+// fixtureSteam builds a Steam root with DayZ and its Workshop content in a
+// second library, as when the game lives on another drive
+func fixtureSteam(t *testing.T) (root, library string) {
+	tmp := t.TempDir()
+	root = filepath.Join(tmp, "Steam")
+	library = filepath.Join(tmp, "SteamLibrary")
+
+	writeFile(t, filepath.Join(root, "steamapps", "libraryfolders.vdf"), `"libraryfolders"
+{
+	"0"
+	{
+		"path"		"`+vdfPath(root)+`"
+		"apps" { "228980" "1" }
+	}
+	"1"
+	{
+		"path"		"`+vdfPath(library)+`"
+		"apps" { "221100" "1" }
+	}
+}`)
+	writeFile(t, filepath.Join(library, "steamapps", "appmanifest_221100.acf"), `"AppState"
+{
+	"appid"		"221100"
+	"installdir"		"DayZ"
+}`)
+	mkdir(t, filepath.Join(library, "steamapps", "common", "DayZ"))
+	mkdir(t, filepath.Join(library, "steamapps", "workshop", "content", "221100"))
+	return root, library
+}
+
+func TestOpenSecondaryLibrary(t *testing.T) {
+	root, library := fixtureSteam(t)
+
+	s, err := Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Libraries) != 2 {
+		t.Fatalf("got %d libraries, want 2: %+v", len(s.Libraries), s.Libraries)
+	}
+	if s.Libraries[0].Path != root || !s.Libraries[0].Apps[228980] {
+		t.Errorf("root library = %+v", s.Libraries[0])
+	}
+
+	if got, want := s.AppInstallDir(dayz.AppID), filepath.Join(library, "steamapps", "common", "DayZ"); got != want {
+		t.Errorf("AppInstallDir() = %q, want %q", got, want)
+	}
+	if got, want := s.WorkshopContentDir(dayz.AppID), filepath.Join(library, "steamapps", "workshop", "content", "221100"); got != want {
+		t.Errorf("WorkshopContentDir() = %q, want %q", got, want)
+	}
+	if got := s.AppInstallDir(1151700); got != "" {
+		t.Errorf("AppInstallDir(not installed) = %q, want empty", got)
+	}
+}
+
+func TestOpenManifestWithoutAppsList(t *testing.T) {
+	// Old clients list no apps; the manifest alone identifies the library
+	root, library := fixtureSteam(t)
+	writeFile(t, filepath.Join(root, "steamapps", "libraryfolders.vdf"), `"LibraryFolders"
+{
+	"1"		"`+vdfPath(library)+`"
+}`)
+
+	s, err := Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := s.AppInstallDir(dayz.AppID), filepath.Join(library, "steamapps", "common", "DayZ"); got != want {
+		t.Errorf("AppInstallDir() = %q, want %q", got, want)
+	}
+}
+
+func TestOpenBrokenRoot(t *testing.T) {
+	tmp := t.TempDir()
+
+	if _, err := Open(filepath.Join(tmp, "missing")); err == nil {
+		t.Error("Open(missing root) succeeded")
+	}
+
+	notDir := filepath.Join(tmp, "file")
+	writeFile(t, filepath.Join(notDir, "steamapps"), "")
+	if _, err := Open(notDir); err == nil {
+		t.Error("Open(steamapps is a file) succeeded")
+	}
+
+	// A garbled libraryfolders.vdf leaves just the root library
+	broken := filepath.Join(tmp, "broken")
+	writeFile(t, filepath.Join(broken, "steamapps", "libraryfolders.vdf"), `"libraryfolders" { "1" {`)
+	s, err := Open(broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Libraries) != 1 || s.Libraries[0].Path != broken {
+		t.Errorf("libraries = %+v, want only the root", s.Libraries)
+	}
+	if got := s.AppInstallDir(dayz.AppID); got != "" {
+		t.Errorf("AppInstallDir() = %q, want empty", got)
+	}
+	if got := s.WorkshopContentDir(dayz.AppID); got != "" {
+		t.Errorf("WorkshopContentDir() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,111 @@
+package steamworks
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// CallbackAvatarImageLoaded is AvatarImageLoaded_t
+const CallbackAvatarImageLoaded = 334
+
+// AvatarSize picks which of a user's avatar images to fetch
+type AvatarSize int
+
+const (
+	AvatarSmall  AvatarSize = iota // 32x32
+	AvatarMedium                   // 64x64
+)
+
+// AvatarNone is what GetFriendAvatar returns when Steam has no image for
+// the user yet. The small and medium getters don't tell "no avatar" from
+// "not downloaded", so callers ask for it with RequestUserInformation.
+const AvatarNone int32 = 0
+
+// AvatarImageLoaded is AvatarImageLoaded_t: an avatar that was AvatarNone
+// is now available under Image
+type AvatarImageLoaded struct {
+	SteamID uint64
+	Image   int32
+	Width   int32
+	Height  int32
+}
+
+// ErrNoImage means the image handle is invalid or not loaded yet
+var ErrNoImage = errors.New("steam image not available")
+
+var (
+	ptrSteamUtils uintptr
+
+	f_GetSmallFriendAvatar   func(uintptr, uint64) int32
+	f_GetMediumFriendAvatar  func(uintptr, uint64) int32
+	f_RequestUserInformation func(uintptr, uint64, bool) bool
+	f_GetImageSize           func(uintptr, int32, *uint32, *uint32) bool
+	f_GetImageRGBA           func(uintptr, int32, *byte, int32) bool
+)
+
+func bindAvatars(lib uintptr) {
+	var getUtils func() uintptr
+	for _, name := range []string{"SteamAPI_SteamUtils", "SteamAPI_SteamUtils_v010", "SteamAPI_SteamUtils_v009"} {
+		bindSafe(&getUtils, lib, name)
+		if getUtils != nil {
+			ptrSteamUtils = getUtils()
+			break
+		}
+	}
+
+	bindSafe(&f_GetSmallFriendAvatar, lib, "SteamAPI_ISteamFriends_GetSmallFriendAvatar")
+	bindSafe(&f_GetMediumFriendAvatar, lib, "SteamAPI_ISteamFriends_GetMediumFriendAvatar")
+	bindSafe(&f_RequestUserInformation, lib, "SteamAPI_ISteamFriends_RequestUserInformation")
+	bindSafe(&f_GetImageSize, lib, "SteamAPI_ISteamUtils_GetImageSize")
+	bindSafe(&f_GetImageRGBA, lib, "SteamAPI_ISteamUtils_GetImageRGBA")
+}
+
+// GetFriendAvatar returns the image handle of a user's avatar, or
+// AvatarNone. The handle changes when the avatar does.
+func GetFriendAvatar(steamID uint64, size AvatarSize) int32 {
+	if !initialized || ptrSteamFriends == 0 {
+		return AvatarNone
+	}
+	switch size {
+	case AvatarMedium:
+		if f_GetMediumFriendAvatar != nil {
+			return f_GetMediumFriendAvatar(ptrSteamFriends, steamID)
+		}
+	default:
+		if f_GetSmallFriendAvatar != nil {
+			return f_GetSmallFriendAvatar(ptrSteamFriends, steamID)
+		}
+	}
+	return AvatarNone
+}
+
+// RequestUserInformation asks Steam for a user's persona data, including
+// the avatar unless nameOnly is set. It returns true if a request went out,
+// in which case PersonaStateChange follows; false means Steam has it already.
+func RequestUserInformation(steamID uint64, nameOnly bool) bool {
+	if !initialized || ptrSteamFriends == 0 || f_RequestUserInformation == nil {
+		return false
+	}
+	return f_RequestUserInformation(ptrSteamFriends, steamID, nameOnly)
+}
+
+// GetImage copies a loaded Steam image (e.g. an avatar handle) out as RGBA
+func GetImage(handle int32) (*image.RGBA, error) {
+	if !initialized || ptrSteamUtils == 0 || f_GetImageSize == nil || f_GetImageRGBA == nil {
+		return nil, fmt.Errorf("utils interface not available")
+	}
+	if handle <= 0 {
+		return nil, ErrNoImage
+	}
+
+	var w, h uint32
+	if !f_GetImageSize(ptrSteamUtils, handle, &w, &h) || w == 0 || h == 0 {
+		return nil, ErrNoImage
+	}
+	img := image.NewRGBA(image.Rect(0, 0, int(w), int(h)))
+	if !f_GetImageRGBA(ptrSteamUtils, handle, &img.Pix[0], int32(len(img.Pix))) {
+		return nil, ErrNoImage
+	}
+	return img, nil
+}
@@ -0,0 +1,307 @@
+package steamworks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// Callback IDs (k_iCallback) decoded by the manual dispatcher
+const (
+	CallbackPersonaStateChange        = 304
+	CallbackPublishedFileSubscribed   = 1321
+	CallbackPublishedFileUnsubscribed = 1322
+	CallbackItemInstalled             = 3405
+	CallbackDownloadItemResult        = 3406
+
+	callbackSteamAPICallCompleted = 703
+)
+
+// ItemInstalled is ItemInstalled_t: a Workshop item finished installing or updating
+type ItemInstalled struct {
+	AppID           uint32
+	PublishedFileID uint64
+}
+
+// DownloadItemResult is DownloadItemResult_t: the outcome of a download.
+// Result is an EResult, 1 meaning OK.
+type DownloadItemResult struct {
+	AppID           uint32
+	PublishedFileID uint64
+	Result          int32
+}
+
+// PersonaStateChange is PersonaStateChange_t: a friend's (or our own)
+// name, status, game or avatar changed. ChangeFlags is EPersonaChange.
+type PersonaStateChange struct {
+	SteamID     uint64
+	ChangeFlags int32
+}
+
+// EPersonaChange flags in PersonaStateChange
+const (
+	PersonaChangeName   int32 = 0x0001
+	PersonaChangeAvatar int32 = 0x0040
+)
+
+// PublishedFileSubscribed is RemoteStoragePublishedFileSubscribed_t
+type PublishedFileSubscribed struct {
+	PublishedFileID uint64
+	AppID           uint32
+}
+
+// PublishedFileUnsubscribed is RemoteStoragePublishedFileUnsubscribed_t
+type PublishedFileUnsubscribed struct {
+	PublishedFileID uint64
+	AppID           uint32
+}
+
+// callbackMsg mirrors CallbackMsg_t. Steam fills it in, so the trailing
+// padding difference between platforms doesn't matter.
+type callbackMsg struct {
+	SteamUser int32
+	Callback  int32
+	Param     *byte
+	ParamSize int32
+}
+
+var (
+	f_ManualDispatchInit     func()
+	f_ManualDispatchRunFrame func(int32)
+	f_ManualDispatchGetNext  func(int32, *callbackMsg) bool
+	f_ManualDispatchFreeLast func(int32)
+	f_GetHSteamPipe          func() int32
+	f_GetAPICallResult       func(int32, uint64, *byte, int32, int32, *bool) bool
+
+	manualDispatch bool
+	steamPipe      int32
+
+	handlerMu       sync.Mutex
+	callbackHandler func(event interface{})
+
+	// Async calls waiting for their SteamAPICallCompleted_t
+	callResultsMu sync.Mutex
+	callResults   = make(map[uint64]chan callResult)
+)
+
+// callResult is a completed async call: the raw result struct and its
+// callback ID, or Failed if Steam reported an IO failure
+type callResult struct {
+	Callback int32
+	Data     []byte
+	Failed   bool
+}
+
+// bindManualDispatch switches Steam to manual callback dispatch. Must run
+// right after SteamAPI_Init, before any callback is queued.
+func bindManualDispatch(lib uintptr) {
+	bindSafe(&f_ManualDispatchInit, lib, "SteamAPI_ManualDispatch_Init")
+	bindSafe(&f_ManualDispatchRunFrame, lib, "SteamAPI_ManualDispatch_RunFrame")
+	bindSafe(&f_ManualDispatchGetNext, lib, "SteamAPI_ManualDispatch_GetNextCallback")
+	bindSafe(&f_ManualDispatchFreeLast, lib, "SteamAPI_ManualDispatch_FreeLastCallback")
+	bindSafe(&f_GetHSteamPipe, lib, "SteamAPI_GetHSteamPipe")
+	bindSafe(&f_GetAPICallResult, lib, "SteamAPI_ManualDispatch_GetAPICallResult")
+
+	if f_ManualDispatchInit == nil || f_ManualDispatchRunFrame == nil || f_ManualDispatchGetNext == nil ||
+		f_ManualDispatchFreeLast == nil || f_GetHSteamPipe == nil {
+		fmt.Println("[Steamworks] Manual dispatch not available, falling back to SteamAPI_RunCallbacks")
+		return
+	}
+
+	f_ManualDispatchInit()
+	steamPipe = f_GetHSteamPipe()
+	manualDispatch = true
+}
+
+// SetCallbackHandler registers fn to receive decoded callbacks (ItemInstalled,
+// DownloadItemResult, ...) from RunCallbacks. It runs on the dispatching
+// goroutine, so it should return quickly.
+func SetCallbackHandler(fn func(event interface{})) {
+	handlerMu.Lock()
+	callbackHandler = fn
+	handlerMu.Unlock()
+}
+
+// HasCallbacks reports whether callbacks are delivered as events. Without
+// manual dispatch callers have to keep polling.
+func HasCallbacks() bool {
+	return initialized && manualDispatch
+}
+
+// dispatchCallbacks pumps the Steam pipe and hands decoded events to the handler
+func dispatchCallbacks() {
+	handlerMu.Lock()
+	handler := callbackHandler
+	handlerMu.Unlock()
+
+	f_ManualDispatchRunFrame(steamPipe)
+
+	var msg callbackMsg
+	for f_ManualDispatchGetNext(steamPipe, &msg) {
+		var event interface{}
+		if msg.Param != nil && msg.ParamSize > 0 {
+			data := unsafe.Slice(msg.Param, msg.ParamSize)
+			if msg.Callback == callbackSteamAPICallCompleted {
+				// Must be fetched before the message is freed
+				completeCallResult(data)
+			} else {
+				event = decodeCallback(int(msg.Callback), data)
+			}
+		}
+		f_ManualDispatchFreeLast(steamPipe)
+
+		if event != nil && handler != nil {
+			handler(event)
+		}
+	}
+}
+
+// startCall issues an async Steam call and registers for its result. The
+// lock is held across the call so the result can't be dispatched before
+// anyone is waiting for it. Returns a nil channel if the call failed.
+func startCall(issue func() uint64) (uint64, chan callResult) {
+	if !manualDispatch || f_GetAPICallResult == nil {
+		return 0, nil
+	}
+	callResultsMu.Lock()
+	defer callResultsMu.Unlock()
+
+	call := issue()
+	if call == 0 { // k_uAPICallInvalid
+		return 0, nil
+	}
+	ch := make(chan callResult, 1)
+	callResults[call] = ch
+	return call, ch
+}
+
+// abandonCall stops waiting for a call, e.g. on timeout
+func abandonCall(call uint64) {
+	callResultsMu.Lock()
+	delete(callResults, call)
+	callResultsMu.Unlock()
+}
+
+// completeCallResult handles SteamAPICallCompleted_t by copying the result
+// out to whoever started the call
+func completeCallResult(data []byte) {
+	r := structReader{data: data}
+	call := r.u64()
+	id := int32(r.u32())
+	size := r.u32()
+	if r.short {
+		return
+	}
+
+	callResultsMu.Lock()
+	ch, ok := callResults[call]
+	delete(callResults, call)
+	callResultsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	res := callResult{Callback: id, Data: make([]byte, size)}
+	var buf *byte
+	if size > 0 {
+		buf = &res.Data[0]
+	}
+	if !f_GetAPICallResult(steamPipe, call, buf, int32(size), id, &res.Failed) {
+		res.Failed = true
+	}
+	ch <- res
+}
+
+// decodeCallback turns a raw callback payload into one of the exported types.
+// Offsets follow the platform's callback packing (see callbackPack).
+func decodeCallback(id int, data []byte) interface{} {
+	r := structReader{data: data}
+	switch id {
+	case CallbackItemInstalled:
+		ev := ItemInstalled{AppID: r.u32()}
+		ev.PublishedFileID = r.u64()
+		return decoded(r, ev)
+	case CallbackDownloadItemResult:
+		ev := DownloadItemResult{AppID: r.u32()}
+		ev.PublishedFileID = r.u64()
+		ev.Result = int32(r.u32())
+		return decoded(r, ev)
+	case CallbackPersonaStateChange:
+		ev := PersonaStateChange{SteamID: r.u64()}
+		ev.ChangeFlags = int32(r.u32())
+		return decoded(r, ev)
+	case CallbackPublishedFileSubscribed:
+		ev := PublishedFileSubscribed{PublishedFileID: r.u64()}
+		ev.AppID = r.u32()
+		return decoded(r, ev)
+	case CallbackPublishedFileUnsubscribed:
+		ev := PublishedFileUnsubscribed{PublishedFileID: r.u64()}
+		ev.AppID = r.u32()
+		return decoded(r, ev)
+	case CallbackFriendRichPresenceUpdate:
+		ev := FriendRichPresenceUpdate{SteamID: r.u64()}
+		ev.AppID = r.u32()
+		return decoded(r, ev)
+	case CallbackGameRichPresenceJoinRequested:
+		ev := GameRichPresenceJoinRequested{SteamID: r.u64()}
+		ev.Connect = r.str(RichPresenceMaxValueLen)
+		return decoded(r, ev)
+	case CallbackAvatarImageLoaded:
+		ev := AvatarImageLoaded{SteamID: r.u64()}
+		ev.Image = int32(r.u32())
+		ev.Width = int32(r.u32())
+		ev.Height = int32(r.u32())
+		return decoded(r, ev)
+	}
+	return nil
+}
+
+func decoded(r structReader, ev interface{}) interface{} {
+	if r.short {
+		return nil
+	}
+	return ev
+}
+
+// structReader reads fields of a packed C struct in declaration order
+type structReader struct {
+	data  []byte
+	off   int
+	short bool
+}
+
+func (r *structReader) field(size int) []byte {
+	align := size
+	if align > callbackPack {
+		align = callbackPack
+	}
+	r.off = (r.off + align - 1) / align * align
+	if r.off+size > len(r.data) {
+		r.short = true
+		return make([]byte, size)
+	}
+	b := r.data[r.off : r.off+size]
+	r.off += size
+	return b
+}
+
+func (r *structReader) u32() uint32 { return binary.LittleEndian.Uint32(r.field(4)) }
+func (r *structReader) u64() uint64 { return binary.LittleEndian.Uint64(r.field(8)) }
+func (r *structReader) flag() bool  { return r.field(1)[0] != 0 }
+
+// str reads a fixed size char array, stopping at the first NUL
+func (r *structReader) str(size int) string {
+	// char arrays only need byte alignment
+	if r.off+size > len(r.data) {
+		r.short = true
+		return ""
+	}
+	b := r.data[r.off : r.off+size]
+	r.off += size
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
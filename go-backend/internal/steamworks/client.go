@@ -0,0 +1,119 @@
+package steamworks
+
+import (
+	"context"
+	"image"
+)
+
+// SteamClient is the slice of the Steamworks API the launcher depends on.
+// The app talks to Steam only through this interface so the native DLL can
+// be swapped for a fake (see package steamfake).
+type SteamClient interface {
+	// Lifecycle
+	Init() error
+	IsInitialized() bool
+	RunCallbacks()
+	SetCallbackHandler(fn func(event interface{}))
+	HasCallbacks() bool
+
+	// Persona and friends
+	GetPersonaName() string
+	GetSteamID() uint64
+	GetFriends() []SteamFriend
+	GetFriendAvatar(steamID uint64, size AvatarSize) int32
+	RequestUserInformation(steamID uint64, nameOnly bool) bool
+	GetImage(handle int32) (*image.RGBA, error)
+
+	// Rich presence
+	SetRichPresence(key, value string) bool
+	ClearRichPresence()
+	GetFriendRichPresence(steamID uint64, key string) string
+	RequestFriendRichPresence(steamID uint64)
+
+	// Invites and chat
+	InviteUserToGame(steamID uint64, connect string) bool
+	SendFriendMessage(steamID uint64, message string) bool
+	OpenChat(steamID uint64) bool
+
+	// UGC
+	SubscribeMod(modId string) error
+	UnsubscribeMod(modId string) error
+	GetSubscribedItems() []uint64
+	GetItemState(modId string) uint32
+	GetDownloadInfo(modId string) (uint64, uint64)
+	DownloadItem(modId string, highPriority bool) bool
+	GetItemInstallInfo(modId string) (string, uint64, uint32)
+	ResolveModPath(modId string) string
+	QueryUGCDetails(ctx context.Context, ids []uint64) ([]UGCDetails, error)
+
+	// Apps
+	GetAppInstallDir(appID uint32) string
+	HasDLCChecks() bool
+	IsDlcInstalled(appID uint32) bool
+	IsSubscribedApp(appID uint32) bool
+}
+
+// Native returns the SteamClient backed by the real Steamworks library
+func Native() SteamClient {
+	return nativeClient{}
+}
+
+type nativeClient struct{}
+
+func (nativeClient) Init() error               { return Init() }
+func (nativeClient) IsInitialized() bool       { return IsInitialized() }
+func (nativeClient) RunCallbacks()             { RunCallbacks() }
+func (nativeClient) HasCallbacks() bool        { return HasCallbacks() }
+func (nativeClient) GetPersonaName() string    { return GetPersonaName() }
+func (nativeClient) GetSteamID() uint64        { return GetSteamID() }
+func (nativeClient) GetFriends() []SteamFriend { return GetFriends() }
+
+func (nativeClient) GetFriendAvatar(steamID uint64, size AvatarSize) int32 {
+	return GetFriendAvatar(steamID, size)
+}
+func (nativeClient) RequestUserInformation(steamID uint64, nameOnly bool) bool {
+	return RequestUserInformation(steamID, nameOnly)
+}
+func (nativeClient) GetImage(handle int32) (*image.RGBA, error) { return GetImage(handle) }
+
+func (nativeClient) SetRichPresence(key, value string) bool { return SetRichPresence(key, value) }
+func (nativeClient) ClearRichPresence()                     { ClearRichPresence() }
+func (nativeClient) GetFriendRichPresence(steamID uint64, key string) string {
+	return GetFriendRichPresence(steamID, key)
+}
+func (nativeClient) RequestFriendRichPresence(steamID uint64) { RequestFriendRichPresence(steamID) }
+
+func (nativeClient) InviteUserToGame(steamID uint64, connect string) bool {
+	return InviteUserToGame(steamID, connect)
+}
+func (nativeClient) SendFriendMessage(steamID uint64, message string) bool {
+	return SendFriendMessage(steamID, message)
+}
+func (nativeClient) OpenChat(steamID uint64) bool { return OpenChat(steamID) }
+
+func (nativeClient) SetCallbackHandler(fn func(event interface{})) {
+	SetCallbackHandler(fn)
+}
+
+func (nativeClient) SubscribeMod(modId string) error   { return SubscribeMod(modId) }
+func (nativeClient) UnsubscribeMod(modId string) error { return UnsubscribeMod(modId) }
+func (nativeClient) GetSubscribedItems() []uint64      { return GetSubscribedItems() }
+func (nativeClient) GetItemState(modId string) uint32  { return GetItemState(modId) }
+func (nativeClient) GetDownloadInfo(modId string) (uint64, uint64) {
+	return GetDownloadInfo(modId)
+}
+func (nativeClient) DownloadItem(modId string, highPriority bool) bool {
+	return DownloadItem(modId, highPriority)
+}
+func (nativeClient) GetItemInstallInfo(modId string) (string, uint64, uint32) {
+	return GetItemInstallInfo(modId)
+}
+func (nativeClient) ResolveModPath(modId string) string { return ResolveModPath(modId) }
+func (nativeClient) QueryUGCDetails(ctx context.Context, ids []uint64) ([]UGCDetails, error) {
+	return QueryUGCDetails(ctx, ids)
+}
+
+func (nativeClient) GetAppInstallDir(appID uint32) string { return GetAppInstallDir(appID) }
+func (nativeClient) HasDLCChecks() bool                   { return HasDLCChecks() }
+func (nativeClient) IsDlcInstalled(appID uint32) bool     { return IsDlcInstalled(appID) }
+func (nativeClient) IsSubscribedApp(appID uint32) bool    { return IsSubscribedApp(appID) }
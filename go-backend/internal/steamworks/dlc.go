@@ -0,0 +1,34 @@
+package steamworks
+
+var (
+	f_BIsDlcInstalled  func(uintptr, uint32) bool
+	f_BIsSubscribedApp func(uintptr, uint32) bool
+)
+
+func bindDLC(lib uintptr) {
+	bindSafe(&f_BIsDlcInstalled, lib, "SteamAPI_ISteamApps_BIsDlcInstalled")
+	bindSafe(&f_BIsSubscribedApp, lib, "SteamAPI_ISteamApps_BIsSubscribedApp")
+}
+
+// HasDLCChecks reports whether DLC ownership can be asked at all. Without
+// it IsSubscribedApp and IsDlcInstalled return false for every app.
+func HasDLCChecks() bool {
+	return initialized && ptrSteamApps != 0 && f_BIsDlcInstalled != nil && f_BIsSubscribedApp != nil
+}
+
+// IsDlcInstalled reports whether the user owns DLC appID and it's installed
+func IsDlcInstalled(appID uint32) bool {
+	if !initialized || ptrSteamApps == 0 || f_BIsDlcInstalled == nil {
+		return false
+	}
+	return f_BIsDlcInstalled(ptrSteamApps, appID)
+}
+
+// IsSubscribedApp reports whether the user owns appID (a game or DLC),
+// installed or not
+func IsSubscribedApp(appID uint32) bool {
+	if !initialized || ptrSteamApps == 0 || f_BIsSubscribedApp == nil {
+		return false
+	}
+	return f_BIsSubscribedApp(ptrSteamApps, appID)
+}
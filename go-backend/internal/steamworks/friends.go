@@ -4,6 +4,9 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamid"
 )
 
 const (
@@ -22,12 +25,15 @@ type FriendGameInfo_t struct {
 
 // SteamFriend info struct for frontend
 type SteamFriend struct {
-	SteamID     uint64 `json:"steamId"`
-	Name        string `json:"name"`
-	IsOnline    bool   `json:"isOnline"`
-	IsPlaying   bool   `json:"isPlaying"`
-	GameName    string `json:"gameName"` // Only set if IsPlaying is true
-	GameAddress string `json:"gameAddress"`
+	SteamID     steamid.ID `json:"steamId"`
+	Name        string     `json:"name"`
+	IsOnline    bool       `json:"isOnline"`
+	IsPlaying   bool       `json:"isPlaying"`
+	GameName    string     `json:"gameName"` // Only set if IsPlaying is true
+	GameAddress string     `json:"gameAddress"`
+	GameIP      string     `json:"gameIp,omitempty"`
+	GamePort    int        `json:"gamePort,omitempty"`
+	QueryPort   int        `json:"queryPort,omitempty"` // 0 if the server didn't report one
 }
 
 var (
@@ -39,8 +45,8 @@ var (
 	f_GetFriendPersonaState func(uintptr, uint64) int
 )
 
-// Helper to convert uint32 IP to string (Big Endian usually for network, but Valve sends Host order/Little Endian often?
-// Actually Steam IPs are usually uint32. Let's try standard conversion.)
+// int2ip formats an IPv4 address Steam hands out in host order, i.e. with
+// the first octet in the most significant byte
 func int2ip(nn uint32) string {
 	ip := make(net.IP, 4)
 	binary.BigEndian.PutUint32(ip, nn)
@@ -91,18 +97,22 @@ func GetFriends() []SteamFriend {
 		// EPersonaState 0 = Offline. Anything else is some form of online (Online, Busy, Away, Snooze, etc.)
 		isOnline := state != 0
 
-		dayzAppID := uint64(221100)
 		gameName := ""
 		gameAddress := ""
+		gameIP := ""
+		gamePort, queryPort := 0, 0
 
 		if isPlaying {
-			if gameInfo.GameID == dayzAppID {
+			if gameInfo.GameID == dayz.AppID {
 				gameName = "DayZ"
 				if gameInfo.GameIP != 0 {
-					// Convert IP
-					ip := make(net.IP, 4)
-					binary.LittleEndian.PutUint32(ip, gameInfo.GameIP)
-					gameAddress = fmt.Sprintf("%s:%d", ip.String(), gameInfo.GamePort)
+					gameIP = int2ip(gameInfo.GameIP)
+					gameAddress = fmt.Sprintf("%s:%d", gameIP, gameInfo.GamePort)
+					gamePort = int(gameInfo.GamePort)
+					// 0xFFFF/0xFFFE mean the query port isn't known
+					if gameInfo.QueryPort < 0xFFFE {
+						queryPort = int(gameInfo.QueryPort)
+					}
 				}
 			} else {
 				gameName = "Other Game"
@@ -110,12 +120,15 @@ func GetFriends() []SteamFriend {
 		}
 
 		friends = append(friends, SteamFriend{
-			SteamID:     steamID,
+			SteamID:     steamid.ID(steamID),
 			Name:        name,
 			IsOnline:    isOnline,
 			IsPlaying:   isPlaying,
 			GameName:    gameName,
 			GameAddress: gameAddress,
+			GameIP:      gameIP,
+			GamePort:    gamePort,
+			QueryPort:   queryPort,
 		})
 	}
 
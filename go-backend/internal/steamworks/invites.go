@@ -0,0 +1,39 @@
+package steamworks
+
+// CallbackGameRichPresenceJoinRequested is GameRichPresenceJoinRequested_t
+const CallbackGameRichPresenceJoinRequested = 337
+
+// GameRichPresenceJoinRequested is GameRichPresenceJoinRequested_t: the
+// user accepted an invite, or joined a friend, while we are running.
+// Connect is the friend's "connect" rich presence or the invite string.
+type GameRichPresenceJoinRequested struct {
+	SteamID uint64
+	Connect string
+}
+
+var (
+	f_InviteUserToGame     func(uintptr, uint64, string) bool
+	f_ReplyToFriendMessage func(uintptr, uint64, string) bool
+)
+
+func bindInvites(lib uintptr) {
+	bindSafe(&f_InviteUserToGame, lib, "SteamAPI_ISteamFriends_InviteUserToGame")
+	bindSafe(&f_ReplyToFriendMessage, lib, "SteamAPI_ISteamFriends_ReplyToFriendMessage")
+}
+
+// InviteUserToGame sends a Steam game invite carrying connect, which Steam
+// passes to the game (or GameRichPresenceJoinRequested) when accepted
+func InviteUserToGame(steamID uint64, connect string) bool {
+	if !initialized || ptrSteamFriends == 0 || f_InviteUserToGame == nil {
+		return false
+	}
+	return f_InviteUserToGame(ptrSteamFriends, steamID, connect)
+}
+
+// SendFriendMessage posts a chat message to a friend
+func SendFriendMessage(steamID uint64, message string) bool {
+	if !initialized || ptrSteamFriends == 0 || f_ReplyToFriendMessage == nil {
+		return false
+	}
+	return f_ReplyToFriendMessage(ptrSteamFriends, steamID, message)
+}
@@ -0,0 +1,59 @@
+package steamworks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// libraryPath overrides where the Steamworks library is loaded from. It can
+// point at the library itself or the folder holding it.
+var libraryPath = os.Getenv("STEAM_API_LIBRARY")
+
+// SetLibraryPath makes Init load the Steamworks library from path (a file or
+// a directory) before searching next to the executable. Call it before Init.
+func SetLibraryPath(path string) {
+	libraryPath = path
+}
+
+// LibraryLoadedFrom returns the path the Steamworks library was loaded from
+func LibraryLoadedFrom() string {
+	return loadedFrom
+}
+
+var loadedFrom string
+
+// libraryCandidates lists where to look for the library, most specific
+// first. The bare name comes last so the OS loader can search its own paths.
+func libraryCandidates() []string {
+	var paths []string
+	if libraryPath != "" {
+		if info, err := os.Stat(libraryPath); err == nil && info.IsDir() {
+			paths = append(paths, filepath.Join(libraryPath, libraryName))
+		} else {
+			paths = append(paths, libraryPath)
+		}
+	}
+	if exe, err := os.Executable(); err == nil {
+		dir := filepath.Dir(exe)
+		for _, sub := range librarySubdirs {
+			paths = append(paths, filepath.Join(dir, sub, libraryName))
+		}
+	}
+	return append(paths, libraryName)
+}
+
+// loadSteamAPI opens the first candidate that loads
+func loadSteamAPI() (uintptr, error) {
+	var errs []error
+	for _, path := range libraryCandidates() {
+		lib, err := openLibrary(path)
+		if err == nil {
+			loadedFrom = path
+			return lib, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", path, err))
+	}
+	return 0, errors.Join(errs...)
+}
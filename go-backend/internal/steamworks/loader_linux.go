@@ -0,0 +1,16 @@
+package steamworks
+
+import "github.com/ebitengine/purego"
+
+const libraryName = "libsteam_api.so"
+
+// The SDK ships the library in redistributable_bin/linux64, so accept that
+// layout next to the binary as well
+var librarySubdirs = []string{"", "linux64"}
+
+// Callback structs are packed to 4 bytes on Linux (VALVE_CALLBACK_PACK_SMALL)
+const callbackPack = 4
+
+func openLibrary(path string) (uintptr, error) {
+	return purego.Dlopen(path, purego.RTLD_NOW|purego.RTLD_GLOBAL)
+}
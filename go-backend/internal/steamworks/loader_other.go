@@ -0,0 +1,18 @@
+//go:build !windows && !linux
+
+package steamworks
+
+import (
+	"fmt"
+	"runtime"
+)
+
+const libraryName = "libsteam_api"
+
+var librarySubdirs = []string{""}
+
+const callbackPack = 4
+
+func openLibrary(path string) (uintptr, error) {
+	return 0, fmt.Errorf("steamworks is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,15 @@
+package steamworks
+
+import "syscall"
+
+const libraryName = "steam_api64.dll"
+
+var librarySubdirs = []string{""}
+
+// Callback structs are packed to 8 bytes on Windows (VALVE_CALLBACK_PACK_LARGE)
+const callbackPack = 8
+
+func openLibrary(path string) (uintptr, error) {
+	dll, err := syscall.LoadLibrary(path)
+	return uintptr(dll), err
+}
@@ -0,0 +1,32 @@
+package steamworks
+
+var (
+	f_ActivateGameOverlayToUser func(uintptr, string, uint64)
+	f_IsOverlayEnabled          func(uintptr) bool
+)
+
+func bindOverlay(lib uintptr) {
+	bindSafe(&f_ActivateGameOverlayToUser, lib, "SteamAPI_ISteamFriends_ActivateGameOverlayToUser")
+	bindSafe(&f_IsOverlayEnabled, lib, "SteamAPI_ISteamUtils_IsOverlayEnabled")
+}
+
+// IsOverlayEnabled reports whether the Steam overlay is hooked into this
+// process. A launcher usually isn't rendered through it, so callers need
+// a fallback.
+func IsOverlayEnabled() bool {
+	if !initialized || ptrSteamUtils == 0 || f_IsOverlayEnabled == nil {
+		return false
+	}
+	return f_IsOverlayEnabled(ptrSteamUtils)
+}
+
+// OpenChat opens a chat with a friend in the Steam overlay. Returns false
+// if the overlay isn't available; steam://friends/message/<id> is the
+// usual fallback.
+func OpenChat(steamID uint64) bool {
+	if !IsOverlayEnabled() || ptrSteamFriends == 0 || f_ActivateGameOverlayToUser == nil {
+		return false
+	}
+	f_ActivateGameOverlayToUser(ptrSteamFriends, "chat", steamID)
+	return true
+}
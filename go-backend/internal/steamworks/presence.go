@@ -0,0 +1,67 @@
+package steamworks
+
+// CallbackFriendRichPresenceUpdate is FriendRichPresenceUpdate_t
+const CallbackFriendRichPresenceUpdate = 336
+
+// Rich presence limits from isteamfriends.h
+const (
+	RichPresenceMaxKeys     = 30
+	RichPresenceMaxKeyLen   = 64
+	RichPresenceMaxValueLen = 256
+)
+
+// FriendRichPresenceUpdate is FriendRichPresenceUpdate_t: a friend's rich
+// presence changed, or arrived after RequestFriendRichPresence
+type FriendRichPresenceUpdate struct {
+	SteamID uint64
+	AppID   uint32
+}
+
+var (
+	f_SetRichPresence           func(uintptr, string, string) bool
+	f_ClearRichPresence         func(uintptr)
+	f_GetFriendRichPresence     func(uintptr, uint64, string) string
+	f_RequestFriendRichPresence func(uintptr, uint64)
+)
+
+func bindRichPresence(lib uintptr) {
+	bindSafe(&f_SetRichPresence, lib, "SteamAPI_ISteamFriends_SetRichPresence")
+	bindSafe(&f_ClearRichPresence, lib, "SteamAPI_ISteamFriends_ClearRichPresence")
+	bindSafe(&f_GetFriendRichPresence, lib, "SteamAPI_ISteamFriends_GetFriendRichPresence")
+	bindSafe(&f_RequestFriendRichPresence, lib, "SteamAPI_ISteamFriends_RequestFriendRichPresence")
+}
+
+// SetRichPresence sets one of our rich presence keys. An empty value
+// deletes the key. Returns false if Steam rejected it (too long, too many keys).
+func SetRichPresence(key, value string) bool {
+	if !initialized || ptrSteamFriends == 0 || f_SetRichPresence == nil {
+		return false
+	}
+	return f_SetRichPresence(ptrSteamFriends, key, value)
+}
+
+// ClearRichPresence removes all of our rich presence keys
+func ClearRichPresence() {
+	if !initialized || ptrSteamFriends == 0 || f_ClearRichPresence == nil {
+		return
+	}
+	f_ClearRichPresence(ptrSteamFriends)
+}
+
+// GetFriendRichPresence reads a friend's rich presence key. Steam only
+// has values for friends in the same game; others return "".
+func GetFriendRichPresence(steamID uint64, key string) string {
+	if !initialized || ptrSteamFriends == 0 || f_GetFriendRichPresence == nil {
+		return ""
+	}
+	return f_GetFriendRichPresence(ptrSteamFriends, steamID, key)
+}
+
+// RequestFriendRichPresence asks Steam to fetch a friend's rich presence;
+// FriendRichPresenceUpdate follows
+func RequestFriendRichPresence(steamID uint64) {
+	if !initialized || ptrSteamFriends == 0 || f_RequestFriendRichPresence == nil {
+		return
+	}
+	f_RequestFriendRichPresence(ptrSteamFriends, steamID)
+}
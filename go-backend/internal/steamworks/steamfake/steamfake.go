@@ -0,0 +1,741 @@
+// Package steamfake is an in-memory steamworks.SteamClient for exercising
+// mod, download and launch flows without the Steam client or its DLL.
+package steamfake
+
+import (
+	"context"
+	"errors"
+	"image"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+// EItemState flags, as returned by GetItemState
+const (
+	StateSubscribed      uint32 = 1
+	StateInstalled       uint32 = 4
+	StateNeedsUpdate     uint32 = 8
+	StateDownloading     uint32 = 16
+	StateDownloadPending uint32 = 32
+)
+
+// ErrOffline is returned by Init while the fake Steam is "closed"
+var ErrOffline = errors.New("steam is not running")
+
+// Item is the fake's view of one Workshop item
+type Item struct {
+	State       uint32
+	Current     uint64 // bytes downloaded so far
+	Total       uint64 // download size, also used as size on disk once installed
+	Path        string
+	TimeUpdated uint32
+}
+
+// DownloadRequest records a DownloadItem call
+type DownloadRequest struct {
+	ModID        string
+	HighPriority bool
+}
+
+// Invite records an InviteUserToGame call
+type Invite struct {
+	SteamID uint64
+	Connect string
+}
+
+// Message records a SendFriendMessage call
+type Message struct {
+	SteamID uint64
+	Text    string
+}
+
+// Client is a scriptable fake. The zero value is not usable, use New.
+type Client struct {
+	mu sync.Mutex
+
+	running     bool
+	initialized bool
+
+	persona string
+	steamID uint64
+	friends []steamworks.SteamFriend
+	apps    map[uint32]string
+	items   map[uint64]*Item
+	details map[uint64]steamworks.UGCDetails
+
+	// Avatars by SteamID as image handles, images by handle. Users in
+	// unloaded have an avatar Steam hasn't fetched; requested ones get a
+	// PersonaStateChange once it arrives.
+	avatars    map[uint64]int32
+	images     map[int32]*image.RGBA
+	nextHandle int32
+	unloaded   map[uint64]bool
+	requested  map[uint64]bool
+
+	// Our rich presence and what friends publish
+	presence       map[string]string
+	friendPresence map[uint64]map[string]string
+
+	// Invites and chat messages sent to friends
+	invites         []Invite
+	messages        []Message
+	invitesDisabled bool
+
+	// The overlay is off by default, as it is for a launcher
+	overlay bool
+	chats   []uint64
+
+	// Owned DLC app IDs, true when installed
+	dlc         map[uint32]bool
+	noDLCChecks bool
+
+	// installRoot is where finished downloads are "installed" (paths only,
+	// nothing is written to disk)
+	installRoot string
+	rate        uint64
+	requests    []DownloadRequest
+
+	// Callbacks queued until the next RunCallbacks, like the real pipe
+	handler func(event interface{})
+	pending []interface{}
+}
+
+// New returns a fake with Steam running but not yet initialised
+func New() *Client {
+	return &Client{
+		running:        true,
+		persona:        "Survivor",
+		steamID:        76561197960287930,
+		apps:           make(map[uint32]string),
+		items:          make(map[uint64]*Item),
+		details:        make(map[uint64]steamworks.UGCDetails),
+		avatars:        make(map[uint64]int32),
+		images:         make(map[int32]*image.RGBA),
+		unloaded:       make(map[uint64]bool),
+		requested:      make(map[uint64]bool),
+		presence:       make(map[string]string),
+		friendPresence: make(map[uint64]map[string]string),
+		dlc:            make(map[uint32]bool),
+		installRoot:    filepath.Join("workshop", "content", strconv.Itoa(dayz.AppID)),
+	}
+}
+
+// --- Scripting ---
+
+// SetRunning starts or stops the fake Steam. Stopping it drops the
+// connection, as if the client was closed mid-session.
+func (c *Client) SetRunning(running bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = running
+	if !running {
+		c.initialized = false
+	}
+}
+
+// SetPersona sets the logged in user
+func (c *Client) SetPersona(name string, steamID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.persona, c.steamID = name, steamID
+}
+
+// SetFriends replaces the friends list
+func (c *Client) SetFriends(friends []steamworks.SteamFriend) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.friends = append([]steamworks.SteamFriend(nil), friends...)
+}
+
+// SetAvatar gives a user an avatar (the same image for every size) under a
+// new handle. If the launcher asked for the user's information while the
+// avatar was unloaded, PersonaStateChange with the avatar flag is queued.
+func (c *Client) SetAvatar(steamID uint64, img *image.RGBA) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextHandle++
+	handle := c.nextHandle
+	c.images[handle] = img
+	c.avatars[steamID] = handle
+	delete(c.unloaded, steamID)
+	if c.requested[steamID] {
+		delete(c.requested, steamID)
+		c.pending = append(c.pending, steamworks.PersonaStateChange{
+			SteamID: steamID, ChangeFlags: steamworks.PersonaChangeAvatar,
+		})
+	}
+}
+
+// SetAvatarUnloaded makes a user's avatar report AvatarNone, as Steam does
+// before it has fetched the image, until SetAvatar
+func (c *Client) SetAvatarUnloaded(steamID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.avatars, steamID)
+	c.unloaded[steamID] = true
+}
+
+// RequestedUsers returns the users RequestUserInformation is still waiting on
+func (c *Client) RequestedUsers() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []uint64
+	for id := range c.requested {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// SetFriendRichPresence sets a friend's rich presence key and queues
+// FriendRichPresenceUpdate. An empty value deletes the key.
+func (c *Client) SetFriendRichPresence(steamID uint64, key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	kv := c.friendPresence[steamID]
+	if kv == nil {
+		kv = make(map[string]string)
+		c.friendPresence[steamID] = kv
+	}
+	if value == "" {
+		delete(kv, key)
+	} else {
+		kv[key] = value
+	}
+	c.pending = append(c.pending, steamworks.FriendRichPresenceUpdate{SteamID: steamID, AppID: dayz.AppID})
+}
+
+// RichPresence returns a copy of our own rich presence
+func (c *Client) RichPresence() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]string, len(c.presence))
+	for k, v := range c.presence {
+		out[k] = v
+	}
+	return out
+}
+
+// SetInvitesEnabled makes InviteUserToGame fail (false), as it does for
+// friends who aren't running the game, forcing the chat fallback
+func (c *Client) SetInvitesEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invitesDisabled = !enabled
+}
+
+// Invites returns every invite sent so far
+func (c *Client) Invites() []Invite {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Invite(nil), c.invites...)
+}
+
+// Messages returns every chat message sent so far
+func (c *Client) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.messages...)
+}
+
+// SetOverlayEnabled makes OpenChat succeed through the "overlay"
+func (c *Client) SetOverlayEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overlay = enabled
+}
+
+// OpenedChats returns the friends OpenChat opened an overlay chat with
+func (c *Client) OpenedChats() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]uint64(nil), c.chats...)
+}
+
+// SetDLCChecks makes DLC ownership unavailable, as when the ISteamApps
+// bindings are missing
+func (c *Client) SetDLCChecks(available bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.noDLCChecks = !available
+}
+
+// SetDLC marks DLC appID as owned (and installed or not), or removes it
+// when owned is false
+func (c *Client) SetDLC(appID uint32, owned, installed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if owned {
+		c.dlc[appID] = installed
+	} else {
+		delete(c.dlc, appID)
+	}
+}
+
+// SetAppInstallDir makes GetAppInstallDir report dir for appID
+func (c *Client) SetAppInstallDir(appID uint32, dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apps[appID] = dir
+}
+
+// SetInstallRoot changes the folder finished downloads are placed under
+func (c *Client) SetInstallRoot(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.installRoot = dir
+}
+
+// SetItem adds or replaces an item
+func (c *Client) SetItem(modId string, item Item) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cp := item
+	c.items[parseID(modId)] = &cp
+}
+
+// Item returns a copy of the item's current state
+func (c *Client) Item(modId string) (Item, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it, ok := c.items[parseID(modId)]
+	if !ok {
+		return Item{}, false
+	}
+	return *it, true
+}
+
+// SetDetails sets what QueryUGCDetails returns for d.PublishedFileID
+func (c *Client) SetDetails(d steamworks.UGCDetails) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.details[d.PublishedFileID] = d
+}
+
+// SetProgress marks an item as downloading with the given byte counts
+func (c *Client) SetProgress(modId string, current, total uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it := c.itemLocked(modId)
+	it.State = (it.State | StateDownloading) &^ StateDownloadPending
+	it.Current, it.Total = current, total
+}
+
+// Install finishes an item's download immediately
+func (c *Client) Install(modId string, timeUpdated uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.installLocked(modId, c.itemLocked(modId), timeUpdated)
+}
+
+// MarkOutdated flags an installed item as needing an update
+func (c *Client) MarkOutdated(modId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.itemLocked(modId).State |= StateNeedsUpdate
+}
+
+// SetDownloadRate makes every RunCallbacks advance pending and active
+// downloads by bytes. Zero (the default) leaves progress to SetProgress.
+func (c *Client) SetDownloadRate(bytes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rate = bytes
+}
+
+// Emit queues a callback for the next RunCallbacks
+func (c *Client) Emit(event interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending = append(c.pending, event)
+}
+
+// DownloadRequests returns every DownloadItem call made so far
+func (c *Client) DownloadRequests() []DownloadRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]DownloadRequest(nil), c.requests...)
+}
+
+// --- steamworks.SteamClient ---
+
+var _ steamworks.SteamClient = (*Client)(nil)
+
+func (c *Client) Init() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.running {
+		return ErrOffline
+	}
+	c.initialized = true
+	return nil
+}
+
+func (c *Client) IsInitialized() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initialized
+}
+
+// RunCallbacks advances simulated downloads when a rate is set, then
+// delivers queued callbacks to the handler
+func (c *Client) RunCallbacks() {
+	c.mu.Lock()
+	if !c.initialized {
+		c.mu.Unlock()
+		return
+	}
+	if c.rate > 0 {
+		for id, it := range c.items {
+			if it.State&(StateDownloading|StateDownloadPending) == 0 {
+				continue
+			}
+			it.State = (it.State | StateDownloading) &^ StateDownloadPending
+			it.Current += c.rate
+			if it.Current >= it.Total {
+				c.installLocked(strconv.FormatUint(id, 10), it, it.TimeUpdated)
+			}
+		}
+	}
+	events, handler := c.pending, c.handler
+	c.pending = nil
+	c.mu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	for _, ev := range events {
+		handler(ev)
+	}
+}
+
+func (c *Client) SetCallbackHandler(fn func(event interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = fn
+}
+
+// HasCallbacks is always true: the fake reports installs and subscriptions
+// as events
+func (c *Client) HasCallbacks() bool {
+	return c.IsInitialized()
+}
+
+func (c *Client) GetPersonaName() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return ""
+	}
+	return c.persona
+}
+
+func (c *Client) GetSteamID() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return 0
+	}
+	return c.steamID
+}
+
+func (c *Client) GetFriends() []steamworks.SteamFriend {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return []steamworks.SteamFriend{}
+	}
+	return append([]steamworks.SteamFriend{}, c.friends...)
+}
+
+func (c *Client) GetFriendAvatar(steamID uint64, size steamworks.AvatarSize) int32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return steamworks.AvatarNone
+	}
+	return c.avatars[steamID]
+}
+
+// RequestUserInformation goes out only for users whose avatar is unloaded
+func (c *Client) RequestUserInformation(steamID uint64, nameOnly bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized || nameOnly || !c.unloaded[steamID] {
+		return false
+	}
+	c.requested[steamID] = true
+	return true
+}
+
+func (c *Client) GetImage(handle int32) (*image.RGBA, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	img, ok := c.images[handle]
+	if !c.initialized || !ok {
+		return nil, steamworks.ErrNoImage
+	}
+	cp := *img
+	cp.Pix = append([]uint8(nil), img.Pix...)
+	return &cp, nil
+}
+
+// SetRichPresence enforces Steam's key and value limits
+func (c *Client) SetRichPresence(key, value string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized || key == "" || len(key) >= steamworks.RichPresenceMaxKeyLen ||
+		len(value) >= steamworks.RichPresenceMaxValueLen {
+		return false
+	}
+	if value == "" {
+		delete(c.presence, key)
+		return true
+	}
+	if _, ok := c.presence[key]; !ok && len(c.presence) >= steamworks.RichPresenceMaxKeys {
+		return false
+	}
+	c.presence[key] = value
+	return true
+}
+
+func (c *Client) ClearRichPresence() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.presence)
+}
+
+func (c *Client) GetFriendRichPresence(steamID uint64, key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return ""
+	}
+	return c.friendPresence[steamID][key]
+}
+
+// RequestFriendRichPresence queues FriendRichPresenceUpdate if the friend
+// has any rich presence
+func (c *Client) RequestFriendRichPresence(steamID uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.initialized && len(c.friendPresence[steamID]) > 0 {
+		c.pending = append(c.pending, steamworks.FriendRichPresenceUpdate{SteamID: steamID, AppID: dayz.AppID})
+	}
+}
+
+func (c *Client) InviteUserToGame(steamID uint64, connect string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized || c.invitesDisabled {
+		return false
+	}
+	c.invites = append(c.invites, Invite{SteamID: steamID, Connect: connect})
+	return true
+}
+
+func (c *Client) SendFriendMessage(steamID uint64, message string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return false
+	}
+	c.messages = append(c.messages, Message{SteamID: steamID, Text: message})
+	return true
+}
+
+func (c *Client) OpenChat(steamID uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized || !c.overlay {
+		return false
+	}
+	c.chats = append(c.chats, steamID)
+	return true
+}
+
+func (c *Client) SubscribeMod(modId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return errors.New("UGC interface not available")
+	}
+	it := c.itemLocked(modId)
+	it.State |= StateSubscribed
+	if it.State&StateInstalled == 0 {
+		it.State |= StateDownloadPending
+	}
+	c.pending = append(c.pending, steamworks.PublishedFileSubscribed{PublishedFileID: parseID(modId), AppID: dayz.AppID})
+	return nil
+}
+
+func (c *Client) UnsubscribeMod(modId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return errors.New("UGC interface not available")
+	}
+	delete(c.items, parseID(modId))
+	c.pending = append(c.pending, steamworks.PublishedFileUnsubscribed{PublishedFileID: parseID(modId), AppID: dayz.AppID})
+	return nil
+}
+
+func (c *Client) GetSubscribedItems() []uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return nil
+	}
+	var ids []uint64
+	for id, it := range c.items {
+		if it.State&StateSubscribed != 0 {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (c *Client) GetItemState(modId string) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return 0
+	}
+	if it, ok := c.items[parseID(modId)]; ok {
+		return it.State
+	}
+	return 0
+}
+
+func (c *Client) GetDownloadInfo(modId string) (uint64, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it, ok := c.items[parseID(modId)]
+	if !c.initialized || !ok || it.State&(StateDownloading|StateDownloadPending) == 0 {
+		return 0, 0
+	}
+	return it.Current, it.Total
+}
+
+func (c *Client) DownloadItem(modId string, highPriority bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return false
+	}
+	c.requests = append(c.requests, DownloadRequest{ModID: modId, HighPriority: highPriority})
+
+	it := c.itemLocked(modId)
+	if it.State&StateDownloading == 0 {
+		it.State |= StateDownloadPending
+		it.Current = 0
+	}
+	return true
+}
+
+func (c *Client) GetItemInstallInfo(modId string) (string, uint64, uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	it, ok := c.items[parseID(modId)]
+	if !c.initialized || !ok || it.State&StateInstalled == 0 {
+		return "", 0, 0
+	}
+	return it.Path, it.Total, it.TimeUpdated
+}
+
+func (c *Client) ResolveModPath(modId string) string {
+	path, _, _ := c.GetItemInstallInfo(modId)
+	return path
+}
+
+// QueryUGCDetails answers from SetDetails. Unknown items come back with
+// EResult 9 (file not found), as Steam does.
+func (c *Client) QueryUGCDetails(ctx context.Context, ids []uint64) ([]steamworks.UGCDetails, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return nil, errors.New("UGC query interface not available")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]steamworks.UGCDetails, 0, len(ids))
+	for _, id := range ids {
+		d, ok := c.details[id]
+		if !ok {
+			d = steamworks.UGCDetails{PublishedFileID: id, Result: 9}
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (c *Client) GetAppInstallDir(appID uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return ""
+	}
+	return c.apps[appID]
+}
+
+func (c *Client) HasDLCChecks() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initialized && !c.noDLCChecks
+}
+
+func (c *Client) IsDlcInstalled(appID uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.initialized && c.dlc[appID]
+}
+
+func (c *Client) IsSubscribedApp(appID uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.initialized {
+		return false
+	}
+	if appID == dayz.AppID {
+		return true
+	}
+	_, ok := c.dlc[appID]
+	return ok
+}
+
+// --- helpers ---
+
+func (c *Client) itemLocked(modId string) *Item {
+	id := parseID(modId)
+	it, ok := c.items[id]
+	if !ok {
+		it = &Item{}
+		c.items[id] = it
+	}
+	return it
+}
+
+func (c *Client) installLocked(modId string, it *Item, timeUpdated uint32) {
+	it.State = (it.State | StateInstalled) &^ (StateDownloading | StateDownloadPending | StateNeedsUpdate)
+	it.Current = it.Total
+	it.TimeUpdated = timeUpdated
+	if it.Path == "" {
+		it.Path = filepath.Join(c.installRoot, modId)
+	}
+	id := parseID(modId)
+	c.pending = append(c.pending,
+		steamworks.DownloadItemResult{AppID: dayz.AppID, PublishedFileID: id, Result: 1},
+		steamworks.ItemInstalled{AppID: dayz.AppID, PublishedFileID: id})
+}
+
+func parseID(modId string) uint64 {
+	id, _ := strconv.ParseUint(modId, 10, 64)
+	return id
+}
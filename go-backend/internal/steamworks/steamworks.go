@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"syscall"
 
 	"github.com/ebitengine/purego"
 )
@@ -33,13 +32,13 @@ func Init() error {
 		return nil
 	}
 
-	// 1. Load Library
-	dll, err := syscall.LoadLibrary("steam_api64.dll")
+	// 1. Load Library (steam_api64.dll / libsteam_api.so, see loader_*.go)
+	lib, err := loadSteamAPI()
 	if err != nil {
-		fmt.Println("[Steamworks] Warning: steam_api64.dll not found. Init failed.")
+		fmt.Printf("[Steamworks] Warning: %s not found. Init failed.\n", libraryName)
 		return err
 	}
-	libHandle = uintptr(dll)
+	libHandle = lib
 
 	// 2. Bind Core Functions
 	// Try standard name first
@@ -57,11 +56,20 @@ func Init() error {
 		if f_Init() {
 			initialized = true
 
+			// Take over callback dispatch before anything gets queued
+			bindManualDispatch(libHandle)
+
 			// 4. Bind Interfaces
 			bindFriends()
 
 			// 5. Initialize UGC/Apps (in ugc.go)
 			InitManualBindings(libHandle)
+			bindUGCQuery(libHandle)
+			bindAvatars(libHandle)
+			bindRichPresence(libHandle)
+			bindInvites(libHandle)
+			bindOverlay(libHandle)
+			bindDLC(libHandle)
 
 			return nil
 		}
@@ -147,7 +155,9 @@ func bindSafe(dest interface{}, lib uintptr, name string) {
 
 // RunCallbacks process Steam events.
 func RunCallbacks() {
-	if initialized && f_RunCallbacks != nil {
+	if initialized && manualDispatch {
+		dispatchCallbacks()
+	} else if initialized && f_RunCallbacks != nil {
 		f_RunCallbacks()
 	}
 }
@@ -134,8 +134,15 @@ func DownloadItem(modId string, highPriority bool) bool {
 }
 
 func ResolveModPath(modId string) string {
+	path, _, _ := GetItemInstallInfo(modId)
+	return path
+}
+
+// GetItemInstallInfo returns the install folder, size on disk and the
+// Workshop update time (Unix seconds) of the installed copy.
+func GetItemInstallInfo(modId string) (string, uint64, uint32) {
 	if ptrSteamUGC == 0 || f_GetItemInstallInfo == nil {
-		return ""
+		return "", 0, 0
 	}
 	id, _ := strconv.ParseUint(modId, 10, 64)
 
@@ -146,7 +153,7 @@ func ResolveModPath(modId string) string {
 	// API: GetItemInstallInfo(id, &size, buf, bufSize, &timestamp)
 	success := f_GetItemInstallInfo(ptrSteamUGC, id, &size, &buf[0], uint32(len(buf)), &timestamp)
 	if !success {
-		return ""
+		return "", 0, 0
 	}
 
 	// C string to Go string
@@ -157,7 +164,7 @@ func ResolveModPath(modId string) string {
 			break
 		}
 	}
-	return string(buf[:n])
+	return string(buf[:n]), size, timestamp
 }
 
 func GetSubscribedItems() []uint64 {
@@ -0,0 +1,246 @@
+package steamworks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// CallbackUGCQueryCompleted is SteamUGCQueryCompleted_t
+	CallbackUGCQueryCompleted = 3401
+
+	// kNumUGCResultsPerPage: the most IDs one details query may hold
+	ugcQueryBatchSize = 50
+
+	ugcQueryInvalid = ^uint64(0) // k_UGCQueryHandleInvalid
+	ugcQueryTimeout = 15 * time.Second
+
+	// Seconds Steam may answer from its own cache
+	ugcQueryCacheAge = 60
+
+	// Sizes from isteamremotestorage.h / steamclientpublic.h
+	cchTitleMax       = 129
+	cchDescriptionMax = 8000
+	cchTagListMax     = 1025
+	cchFilenameMax    = 260
+	cchURLMax         = 256
+	ugcDetailsBufSize = 16 * 1024 // comfortably above sizeof(SteamUGCDetails_t)
+)
+
+// ErrNoCallResults means the loaded Steam API can't deliver async results
+var ErrNoCallResults = errors.New("steam call results not available")
+
+// UGCDetails is SteamUGCDetails_t, plus the preview URL and child items
+// (dependencies) which come from separate calls
+type UGCDetails struct {
+	PublishedFileID uint64   `json:"publishedFileId"`
+	Result          int32    `json:"result"`
+	CreatorAppID    uint32   `json:"creatorAppId"`
+	ConsumerAppID   uint32   `json:"consumerAppId"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Owner           uint64   `json:"owner"`
+	TimeCreated     uint32   `json:"timeCreated"`
+	TimeUpdated     uint32   `json:"timeUpdated"`
+	Visibility      int32    `json:"visibility"`
+	Banned          bool     `json:"banned"`
+	Tags            []string `json:"tags"`
+	FileSize        uint64   `json:"fileSize"`
+	VotesUp         uint32   `json:"votesUp"`
+	VotesDown       uint32   `json:"votesDown"`
+	PreviewURL      string   `json:"previewUrl"`
+	Children        []uint64 `json:"children"`
+}
+
+var (
+	f_CreateQueryUGCDetailsRequest func(uintptr, *uint64, uint32) uint64
+	f_SendQueryUGCRequest          func(uintptr, uint64) uint64
+	f_GetQueryUGCResult            func(uintptr, uint64, uint32, *byte) bool
+	f_GetQueryUGCPreviewURL        func(uintptr, uint64, uint32, *byte, uint32) bool
+	f_GetQueryUGCChildren          func(uintptr, uint64, uint32, *uint64, uint32) bool
+	f_ReleaseQueryUGCRequest       func(uintptr, uint64) bool
+	f_SetReturnChildren            func(uintptr, uint64, bool) bool
+	f_SetReturnLongDescription     func(uintptr, uint64, bool) bool
+	f_SetAllowCachedResponse       func(uintptr, uint64, uint32) bool
+)
+
+func bindUGCQuery(lib uintptr) {
+	bindSafe(&f_CreateQueryUGCDetailsRequest, lib, "SteamAPI_ISteamUGC_CreateQueryUGCDetailsRequest")
+	bindSafe(&f_SendQueryUGCRequest, lib, "SteamAPI_ISteamUGC_SendQueryUGCRequest")
+	bindSafe(&f_GetQueryUGCResult, lib, "SteamAPI_ISteamUGC_GetQueryUGCResult")
+	bindSafe(&f_GetQueryUGCPreviewURL, lib, "SteamAPI_ISteamUGC_GetQueryUGCPreviewURL")
+	bindSafe(&f_GetQueryUGCChildren, lib, "SteamAPI_ISteamUGC_GetQueryUGCChildren")
+	bindSafe(&f_ReleaseQueryUGCRequest, lib, "SteamAPI_ISteamUGC_ReleaseQueryUGCRequest")
+	bindSafe(&f_SetReturnChildren, lib, "SteamAPI_ISteamUGC_SetReturnChildren")
+	bindSafe(&f_SetReturnLongDescription, lib, "SteamAPI_ISteamUGC_SetReturnLongDescription")
+	bindSafe(&f_SetAllowCachedResponse, lib, "SteamAPI_ISteamUGC_SetAllowCachedResponse")
+}
+
+// QueryUGCDetails looks items up through the Steam client instead of the
+// Web API. Results come back in no particular order; items Steam can't
+// resolve are returned with a non-OK Result. Callbacks must be pumped
+// (RunCallbacks) on another goroutine while this waits.
+func QueryUGCDetails(ctx context.Context, ids []uint64) ([]UGCDetails, error) {
+	if !initialized || ptrSteamUGC == 0 || f_CreateQueryUGCDetailsRequest == nil ||
+		f_SendQueryUGCRequest == nil || f_GetQueryUGCResult == nil || f_ReleaseQueryUGCRequest == nil {
+		return nil, fmt.Errorf("UGC query interface not available")
+	}
+
+	var out []UGCDetails
+	for start := 0; start < len(ids); start += ugcQueryBatchSize {
+		end := start + ugcQueryBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch, err := queryUGCBatch(ctx, ids[start:end])
+		if err != nil {
+			return out, err
+		}
+		out = append(out, batch...)
+	}
+	return out, nil
+}
+
+func queryUGCBatch(ctx context.Context, ids []uint64) ([]UGCDetails, error) {
+	handle := f_CreateQueryUGCDetailsRequest(ptrSteamUGC, &ids[0], uint32(len(ids)))
+	if handle == ugcQueryInvalid {
+		return nil, fmt.Errorf("CreateQueryUGCDetailsRequest failed")
+	}
+	defer f_ReleaseQueryUGCRequest(ptrSteamUGC, handle)
+
+	if f_SetReturnChildren != nil {
+		f_SetReturnChildren(ptrSteamUGC, handle, true)
+	}
+	if f_SetReturnLongDescription != nil {
+		f_SetReturnLongDescription(ptrSteamUGC, handle, true)
+	}
+	if f_SetAllowCachedResponse != nil {
+		f_SetAllowCachedResponse(ptrSteamUGC, handle, ugcQueryCacheAge)
+	}
+
+	call, ch := startCall(func() uint64 { return f_SendQueryUGCRequest(ptrSteamUGC, handle) })
+	if ch == nil {
+		return nil, ErrNoCallResults
+	}
+
+	timer := time.NewTimer(ugcQueryTimeout)
+	defer timer.Stop()
+
+	var res callResult
+	select {
+	case res = <-ch:
+	case <-ctx.Done():
+		abandonCall(call)
+		return nil, ctx.Err()
+	case <-timer.C:
+		abandonCall(call)
+		return nil, fmt.Errorf("UGC query timed out")
+	}
+	if res.Failed || res.Callback != CallbackUGCQueryCompleted {
+		return nil, fmt.Errorf("UGC query failed")
+	}
+
+	// SteamUGCQueryCompleted_t: handle, EResult, results returned, total
+	r := structReader{data: res.Data}
+	r.u64()
+	result := int32(r.u32())
+	count := r.u32()
+	if r.short {
+		return nil, fmt.Errorf("UGC query: short result")
+	}
+	if result != 1 {
+		return nil, fmt.Errorf("UGC query failed (EResult %d)", result)
+	}
+
+	details := make([]UGCDetails, 0, count)
+	buf := make([]byte, ugcDetailsBufSize)
+	for i := uint32(0); i < count; i++ {
+		clear(buf)
+		if !f_GetQueryUGCResult(ptrSteamUGC, handle, i, &buf[0]) {
+			continue
+		}
+		d, ok := decodeUGCDetails(buf)
+		if !ok {
+			continue
+		}
+		d.PreviewURL = queryPreviewURL(handle, i)
+		d.Children = queryChildren(handle, i, d.numChildren)
+		details = append(details, d.UGCDetails)
+	}
+	return details, nil
+}
+
+func queryPreviewURL(handle uint64, index uint32) string {
+	if f_GetQueryUGCPreviewURL == nil {
+		return ""
+	}
+	buf := make([]byte, cchURLMax)
+	if !f_GetQueryUGCPreviewURL(ptrSteamUGC, handle, index, &buf[0], uint32(len(buf))) {
+		return ""
+	}
+	r := structReader{data: buf}
+	return r.str(len(buf))
+}
+
+func queryChildren(handle uint64, index uint32, n uint32) []uint64 {
+	if n == 0 || f_GetQueryUGCChildren == nil {
+		return nil
+	}
+	children := make([]uint64, n)
+	if !f_GetQueryUGCChildren(ptrSteamUGC, handle, index, &children[0], n) {
+		return nil
+	}
+	return children
+}
+
+type rawUGCDetails struct {
+	UGCDetails
+	numChildren uint32
+}
+
+// decodeUGCDetails reads SteamUGCDetails_t field by field with the
+// platform's callback packing
+func decodeUGCDetails(data []byte) (rawUGCDetails, bool) {
+	var d rawUGCDetails
+	r := structReader{data: data}
+
+	d.PublishedFileID = r.u64()
+	d.Result = int32(r.u32())
+	r.u32() // m_eFileType
+	d.CreatorAppID = r.u32()
+	d.ConsumerAppID = r.u32()
+	d.Title = r.str(cchTitleMax)
+	d.Description = r.str(cchDescriptionMax)
+	d.Owner = r.u64()
+	d.TimeCreated = r.u32()
+	d.TimeUpdated = r.u32()
+	r.u32() // m_rtimeAddedToUserList
+	d.Visibility = int32(r.u32())
+	d.Banned = r.flag()
+	r.flag() // m_bAcceptedForUse
+	r.flag() // m_bTagsTruncated
+	if tags := r.str(cchTagListMax); tags != "" {
+		d.Tags = strings.Split(tags, ",")
+	}
+	r.u64() // m_hFile
+	r.u64() // m_hPreviewFile
+	r.str(cchFilenameMax)
+	fileSize := r.u32()
+	r.u32() // m_nPreviewFileSize
+	r.str(cchURLMax)
+	d.VotesUp = r.u32()
+	d.VotesDown = r.u32()
+	r.u32() // m_flScore
+	d.numChildren = r.u32()
+	// m_ulTotalFilesSize only exists in newer SDKs; our buffer is zeroed
+	// beyond what Steam writes, so 0 means "not reported"
+	d.FileSize = r.u64()
+	if d.FileSize == 0 {
+		d.FileSize = uint64(fileSize)
+	}
+
+	return d, !r.short && d.PublishedFileID != 0
+}
@@ -0,0 +1,32 @@
+// Package urlscheme registers the launcher as the handler for a custom URL
+// scheme for the current user, so links like hanlauncher://join?... start
+// it (or reach the running instance).
+package urlscheme
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrUnsupported means the scheme has to be declared by the app bundle
+// instead (macOS reads it from Info.plist)
+var ErrUnsupported = errors.New("URL schemes are registered by the app bundle on this platform")
+
+// Register makes exe the handler for scheme://, passing the URL as its
+// first argument. name is shown by the OS where it lists handlers.
+// Registering again with the same values changes nothing.
+func Register(scheme, name, exe string) error {
+	if scheme == "" || exe == "" {
+		return errors.New("urlscheme: scheme and executable required")
+	}
+	return register(scheme, name, exe)
+}
+
+// Executable is the path links should start: the AppImage when running
+// from one, otherwise this binary
+func Executable() (string, error) {
+	if appImage := os.Getenv("APPIMAGE"); appImage != "" {
+		return appImage, nil
+	}
+	return os.Executable()
+}
@@ -0,0 +1,5 @@
+package urlscheme
+
+func register(scheme, name, exe string) error {
+	return ErrUnsupported
+}
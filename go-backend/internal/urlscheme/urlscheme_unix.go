@@ -0,0 +1,62 @@
+//go:build !windows && !darwin
+
+package urlscheme
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// register installs a hidden .desktop entry for x-scheme-handler/<scheme>
+// and makes it the default handler through xdg-mime
+func register(scheme, name, exe string) error {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "applications")
+	file := scheme + "-url-handler.desktop"
+	mime := "x-scheme-handler/" + scheme
+
+	entry := []byte(fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=%s
+Exec=%s %%u
+NoDisplay=true
+Terminal=false
+MimeType=%s;
+`, name, quoteExec(exe), mime))
+
+	path := filepath.Join(dir, file)
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, entry) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, entry, 0644); err != nil {
+		return err
+	}
+	if out, err := exec.Command("xdg-mime", "default", file, mime).CombinedOutput(); err != nil {
+		if out = bytes.TrimSpace(out); len(out) > 0 {
+			return fmt.Errorf("xdg-mime: %v: %s", err, out)
+		}
+		return fmt.Errorf("xdg-mime: %v", err)
+	}
+	return nil
+}
+
+// quoteExec quotes a path for a desktop entry's Exec key. Escapes are
+// doubled: once for the quoted argument, once for the string value.
+func quoteExec(s string) string {
+	r := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", `$`, `\\$`, `%`, `%%`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,31 @@
+package urlscheme
+
+import (
+	"golang.org/x/sys/windows/registry"
+)
+
+// register writes HKCU\Software\Classes\<scheme>, which needs no admin
+// rights and overrides a machine-wide registration
+func register(scheme, name, exe string) error {
+	base := `Software\Classes\` + scheme
+	values := []struct {
+		path, name, value string
+	}{
+		{base, "", "URL:" + name},
+		{base, "URL Protocol", ""},
+		{base + `\DefaultIcon`, "", `"` + exe + `",0`},
+		{base + `\shell\open\command`, "", `"` + exe + `" "%1"`},
+	}
+	for _, v := range values {
+		key, _, err := registry.CreateKey(registry.CURRENT_USER, v.path, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = key.SetStringValue(v.name, v.value)
+		key.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
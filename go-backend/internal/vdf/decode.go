@@ -0,0 +1,207 @@
+package vdf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var nodeType = reflect.TypeOf(Node{})
+
+// Unmarshal parses data and decodes it into v, which must be a pointer.
+// See Decode for the mapping rules.
+func Unmarshal(data []byte, v interface{}) error {
+	root, err := ParseBytes(data)
+	if err != nil {
+		return err
+	}
+	return Decode(root, v)
+}
+
+// Decode fills v from a parsed node. Struct fields are matched to keys
+// case-insensitively, by the `vdf:"name"` tag or the field name; a tag of
+// "-" skips the field. Slice fields collect every entry with a repeated key,
+// maps take the children of a block keyed by name, and *Node or Node fields
+// receive the raw subtree. Missing keys leave fields untouched.
+func Decode(n *Node, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("vdf: Decode needs a non-nil pointer")
+	}
+	return decodeValue(n, rv.Elem())
+}
+
+func decodeValue(n *Node, rv reflect.Value) error {
+	switch {
+	case rv.Type() == nodeType:
+		rv.Set(reflect.ValueOf(*n))
+		return nil
+	case rv.Kind() == reflect.Ptr:
+		if rv.Type().Elem() == nodeType {
+			rv.Set(reflect.ValueOf(n))
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return decodeValue(n, rv.Elem())
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if !n.IsBlock() {
+			return typeError(n, rv)
+		}
+		return decodeStruct(n, rv)
+
+	case reflect.Map:
+		if !n.IsBlock() || rv.Type().Key().Kind() != reflect.String {
+			return typeError(n, rv)
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for _, c := range n.Children {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(c, elem); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(c.Key).Convert(rv.Type().Key()), elem)
+		}
+		return nil
+
+	case reflect.Slice:
+		// Outside a struct a slice takes the children of a block in order
+		if rv.Type().Elem().Kind() == reflect.Uint8 && !n.IsBlock() {
+			return decodeScalar(n, rv)
+		}
+		if !n.IsBlock() {
+			return typeError(n, rv)
+		}
+		return decodeSlice(n.Children, rv)
+
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return typeError(n, rv)
+		}
+		rv.Set(reflect.ValueOf(toInterface(n)))
+		return nil
+	}
+
+	if n.IsBlock() {
+		return typeError(n, rv)
+	}
+	return decodeScalar(n, rv)
+}
+
+func decodeStruct(n *Node, rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("vdf"); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		field := rv.Field(i)
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+			matches := n.GetAll(name)
+			if len(matches) == 0 {
+				continue
+			}
+			if err := decodeSlice(matches, field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c := n.Get(name)
+		if c == nil {
+			continue
+		}
+		if err := decodeValue(c, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeSlice(nodes []*Node, rv reflect.Value) error {
+	out := reflect.MakeSlice(rv.Type(), len(nodes), len(nodes))
+	for i, c := range nodes {
+		if err := decodeValue(c, out.Index(i)); err != nil {
+			return err
+		}
+	}
+	rv.Set(out)
+	return nil
+}
+
+func decodeScalar(n *Node, rv reflect.Value) error {
+	s := n.Value
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Bool:
+		// Steam uses "1"/"0" but accept "true"/"false" as well
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return valueError(n, rv, err)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return valueError(n, rv, err)
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return valueError(n, rv, err)
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return valueError(n, rv, err)
+		}
+		rv.SetFloat(f)
+	case reflect.Slice: // []byte
+		rv.SetBytes([]byte(s))
+	default:
+		return typeError(n, rv)
+	}
+	return nil
+}
+
+// toInterface converts a node for interface{} targets: values become
+// strings and blocks become map[string]interface{} (last duplicate wins)
+func toInterface(n *Node) interface{} {
+	if !n.IsBlock() {
+		return n.Value
+	}
+	m := make(map[string]interface{}, len(n.Children))
+	for _, c := range n.Children {
+		m[c.Key] = toInterface(c)
+	}
+	return m
+}
+
+func typeError(n *Node, rv reflect.Value) error {
+	kind := "value"
+	if n.IsBlock() {
+		kind = "block"
+	}
+	return fmt.Errorf("vdf: cannot decode %s %q into %s", kind, n.Key, rv.Type())
+}
+
+func valueError(n *Node, rv reflect.Value, err error) error {
+	return fmt.Errorf("vdf: cannot decode %q value %q into %s: %w", n.Key, n.Value, rv.Type(), errors.Unwrap(err))
+}
@@ -0,0 +1,78 @@
+package vdf
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+
+// Write serialises the children of root in Steam's tab indented layout.
+// Comments are written back next to the entries they were parsed with.
+func Write(w io.Writer, root *Node) error {
+	bw := bufio.NewWriter(w)
+	for _, c := range root.Children {
+		writeNode(bw, c, 0)
+	}
+	writeComments(bw, root.EndComments, "")
+	return bw.Flush()
+}
+
+// Marshal is Write into a byte slice
+func Marshal(root *Node) []byte {
+	var buf bytes.Buffer
+	Write(&buf, root)
+	return buf.Bytes()
+}
+
+func writeNode(w *bufio.Writer, n *Node, depth int) {
+	indent := strings.Repeat("\t", depth)
+	writeComments(w, n.Comments, indent)
+	w.WriteString(indent)
+	writeQuoted(w, n.Key)
+
+	if !n.IsBlock() {
+		w.WriteString("\t\t")
+		writeQuoted(w, n.Value)
+		writeCond(w, n.Cond)
+		writeLineComment(w, n.LineComment)
+		return
+	}
+
+	writeCond(w, n.Cond)
+	w.WriteString("\n" + indent + "{\n")
+	for _, c := range n.Children {
+		writeNode(w, c, depth+1)
+	}
+	writeComments(w, n.EndComments, indent+"\t")
+	w.WriteString(indent + "}")
+	writeLineComment(w, n.LineComment)
+}
+
+func writeComments(w *bufio.Writer, comments []string, indent string) {
+	for _, c := range comments {
+		w.WriteString(indent + "//" + c + "\n")
+	}
+}
+
+// writeLineComment ends the current line, with its comment if there is one
+func writeLineComment(w *bufio.Writer, comment string) {
+	if comment != "" {
+		w.WriteString("\t//" + comment)
+	}
+	w.WriteByte('\n')
+}
+
+func writeQuoted(w *bufio.Writer, s string) {
+	w.WriteByte('"')
+	escaper.WriteString(w, s)
+	w.WriteByte('"')
+}
+
+func writeCond(w *bufio.Writer, cond string) {
+	if cond != "" {
+		w.WriteString(" [" + cond + "]")
+	}
+}
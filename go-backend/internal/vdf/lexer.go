@@ -0,0 +1,148 @@
+package vdf
+
+import "strings"
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokString
+	tokOpen
+	tokClose
+	tokCond
+	tokComment
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+	// ownLine is set when a line break separates the token from the one
+	// before it, telling trailing comments from comment lines
+	ownLine bool
+}
+
+type lexer struct {
+	src  []byte
+	pos  int
+	line int
+	// line the previous token ended on
+	prevLine int
+}
+
+func (l *lexer) next() (token, error) {
+	t, err := l.scan()
+	t.ownLine = t.line > l.prevLine
+	l.prevLine = l.line
+	return t, err
+}
+
+func (l *lexer) scan() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, line: l.line}, nil
+	}
+
+	c := l.src[l.pos]
+	switch c {
+	case '{':
+		l.pos++
+		return token{kind: tokOpen, line: l.line}, nil
+	case '}':
+		l.pos++
+		return token{kind: tokClose, line: l.line}, nil
+	case '"':
+		return l.quoted()
+	case '[':
+		return l.conditional()
+	case '/':
+		if l.pos+1 < len(l.src) && l.src[l.pos+1] == '/' {
+			return l.comment(), nil
+		}
+	}
+	return l.bare(), nil
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '\n':
+			l.line++
+			l.pos++
+		case ' ', '\t', '\r':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+// comment reads a // comment up to the end of the line, without the slashes
+func (l *lexer) comment() token {
+	start := l.pos + 2
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+	text := strings.TrimRight(string(l.src[start:l.pos]), " \t\r")
+	return token{kind: tokComment, text: text, line: l.line}
+}
+
+// quoted reads a "..." string, processing \n \t \\ and \" escapes
+func (l *lexer) quoted() (token, error) {
+	start := l.line
+	l.pos++ // opening quote
+
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokString, text: sb.String(), line: start}, nil
+		case '\\':
+			if l.pos+1 < len(l.src) {
+				l.pos++
+				switch e := l.src[l.pos]; e {
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				case '\\', '"':
+					sb.WriteByte(e)
+				default:
+					// Unknown escape: keep it verbatim like Steam does
+					sb.WriteByte('\\')
+					sb.WriteByte(e)
+				}
+				l.pos++
+				continue
+			}
+		case '\n':
+			l.line++
+		}
+		sb.WriteByte(c)
+		l.pos++
+	}
+	return token{}, &ParseError{Line: start, Msg: "unterminated string"}
+}
+
+// conditional reads a [$PLATFORM] tag
+func (l *lexer) conditional() (token, error) {
+	start := l.line
+	end := strings.IndexByte(string(l.src[l.pos:]), ']')
+	if end < 0 {
+		return token{}, &ParseError{Line: start, Msg: "unterminated conditional"}
+	}
+	text := string(l.src[l.pos+1 : l.pos+end])
+	l.pos += end + 1
+	return token{kind: tokCond, text: text, line: start}, nil
+}
+
+// bare reads an unquoted token up to whitespace or a structural character
+func (l *lexer) bare() token {
+	start := l.pos
+	for l.pos < len(l.src) && !strings.ContainsRune(" \t\r\n{}\"[", rune(l.src[l.pos])) {
+		l.pos++
+	}
+	return token{kind: tokString, text: string(l.src[start:l.pos]), line: l.line}
+}
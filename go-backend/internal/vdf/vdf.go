@@ -0,0 +1,233 @@
+package vdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Node is a single KeyValues entry. It either holds a string Value or, when
+// Children is non-nil, a block of nested entries. Keys may repeat.
+//
+// Comments hold the text after "//" so files can be rewritten without
+// losing them.
+type Node struct {
+	Key      string
+	Value    string
+	Children []*Node
+	Cond     string // Optional platform conditional, e.g. "$WIN32"
+
+	Comments    []string // Comment lines above the entry
+	LineComment string   // Comment at the end of the entry's (last) line
+	EndComments []string // Comment lines after the last child of a block
+}
+
+// ParseError reports malformed input with the line it was found on
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("vdf: line %d: %s", e.Line, e.Msg)
+}
+
+// IsBlock reports whether the node holds children instead of a value
+func (n *Node) IsBlock() bool {
+	return n.Children != nil
+}
+
+// Get returns the first child with the given key (case-insensitive)
+func (n *Node) Get(key string) *Node {
+	for _, c := range n.Children {
+		if strings.EqualFold(c.Key, key) {
+			return c
+		}
+	}
+	return nil
+}
+
+// GetAll returns every child with the given key, for files with duplicates
+func (n *Node) GetAll(key string) []*Node {
+	var out []*Node
+	for _, c := range n.Children {
+		if strings.EqualFold(c.Key, key) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Path walks nested blocks, e.g. Path("AppState", "UserConfig", "language")
+func (n *Node) Path(keys ...string) *Node {
+	cur := n
+	for _, k := range keys {
+		if cur = cur.Get(k); cur == nil {
+			return nil
+		}
+	}
+	return cur
+}
+
+// String returns the value of a child, or "" if it's missing
+func (n *Node) String(key string) string {
+	if c := n.Get(key); c != nil {
+		return c.Value
+	}
+	return ""
+}
+
+// Set replaces the value of the first child with key, adding it if missing
+func (n *Node) Set(key, value string) {
+	if c := n.Get(key); c != nil && !c.IsBlock() {
+		c.Value = value
+		return
+	}
+	n.Children = append(n.Children, &Node{Key: key, Value: value})
+}
+
+// Add appends a child, keeping any existing entries with the same key
+func (n *Node) Add(child *Node) {
+	n.Children = append(n.Children, child)
+}
+
+// Parse reads text KeyValues. The returned root is a synthetic block whose
+// children are the top level entries of the file.
+func Parse(r io.Reader) (*Node, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data)
+}
+
+// ParseBytes is Parse for data already in memory
+func ParseBytes(data []byte) (*Node, error) {
+	// Steam writes UTF-8 with an optional BOM
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	p := &parser{lex: &lexer{src: data, line: 1}}
+	root := &Node{Children: []*Node{}}
+	if err := p.parseBlock(root, false); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type parser struct {
+	lex    *lexer
+	peeked *token
+}
+
+func (p *parser) next() (token, error) {
+	if p.peeked != nil {
+		t := *p.peeked
+		p.peeked = nil
+		return t, nil
+	}
+	return p.lex.next()
+}
+
+func (p *parser) peek() (token, error) {
+	if p.peeked == nil {
+		t, err := p.lex.next()
+		if err != nil {
+			return t, err
+		}
+		p.peeked = &t
+	}
+	return *p.peeked, nil
+}
+
+func (p *parser) parseBlock(parent *Node, nested bool) error {
+	var last *Node
+	var pending []string
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch t.kind {
+		case tokComment:
+			// A comment on the same line belongs to the entry before it,
+			// anything else to the entry that follows
+			if last != nil && !t.ownLine && last.LineComment == "" {
+				last.LineComment = t.text
+			} else {
+				pending = append(pending, t.text)
+			}
+			continue
+		case tokEOF:
+			if nested {
+				return &ParseError{Line: t.line, Msg: "unexpected end of file, missing '}'"}
+			}
+			parent.EndComments = append(parent.EndComments, pending...)
+			return nil
+		case tokClose:
+			if !nested {
+				return &ParseError{Line: t.line, Msg: "unexpected '}'"}
+			}
+			parent.EndComments = append(parent.EndComments, pending...)
+			return nil
+		case tokOpen:
+			return &ParseError{Line: t.line, Msg: "unexpected '{', expected a key"}
+		case tokCond:
+			return &ParseError{Line: t.line, Msg: "unexpected conditional, expected a key"}
+		}
+
+		node := &Node{Key: t.text, Comments: pending}
+		pending = nil
+
+		v, err := p.next()
+		for err == nil && v.kind == tokComment {
+			// "key" // comment
+			node.Comments = append(node.Comments, v.text)
+			v, err = p.next()
+		}
+		if err != nil {
+			return err
+		}
+		switch v.kind {
+		case tokString:
+			node.Value = v.text
+		case tokOpen:
+			node.Children = []*Node{}
+			if err := p.parseBlock(node, true); err != nil {
+				return err
+			}
+		case tokCond:
+			// Conditional between key and block: "key" [$WIN32] { ... }
+			node.Cond = v.text
+			open, err := p.next()
+			if err != nil {
+				return err
+			}
+			if open.kind != tokOpen {
+				return &ParseError{Line: open.line, Msg: fmt.Sprintf("expected '{' after conditional for %q", node.Key)}
+			}
+			node.Children = []*Node{}
+			if err := p.parseBlock(node, true); err != nil {
+				return err
+			}
+		default:
+			return &ParseError{Line: v.line, Msg: fmt.Sprintf("missing value for key %q", node.Key)}
+		}
+
+		// Trailing conditional: "key" "value" [$WIN32]
+		if node.Cond == "" {
+			c, err := p.peek()
+			if err != nil {
+				return err
+			}
+			if c.kind == tokCond {
+				p.next()
+				node.Cond = c.text
+			}
+		}
+
+		parent.Children = append(parent.Children, node)
+		last = node
+	}
+}
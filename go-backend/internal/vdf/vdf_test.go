@@ -0,0 +1,316 @@
+package vdf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, data string) *Node {
+	t.Helper()
+	root, err := ParseBytes([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestParseEscapes(t *testing.T) {
+	root := mustParse(t, `"root"
+{
+	"path"		"C:\\Program Files (x86)\\Steam"
+	"quote"		"say \"hi\""
+	"multi"		"a\nb\tc"
+	"unknown"	"\d stays"
+	bare		value
+}`)
+	r := root.Get("root")
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"path", `C:\Program Files (x86)\Steam`},
+		{"quote", `say "hi"`},
+		{"multi", "a\nb\tc"},
+		{"unknown", `\d stays`},
+		{"bare", "value"},
+	}
+	for _, tt := range tests {
+		if got := r.String(tt.key); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+
+	// Unknown escapes are read verbatim, so they come back doubled
+	again := mustParse(t, string(Marshal(root))).Get("root")
+	for _, tt := range tests[:4] {
+		if got := again.String(tt.key); got != tt.want {
+			t.Errorf("after Marshal, String(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuplicates(t *testing.T) {
+	root := mustParse(t, `"servers" { "ip" "1.2.3.4" "IP" "5.6.7.8" "ip" { "x" "1" } }`)
+	s := root.Get("SERVERS")
+
+	all := s.GetAll("ip")
+	if len(all) != 3 {
+		t.Fatalf("GetAll() returned %d nodes, want 3", len(all))
+	}
+	if all[0].Value != "1.2.3.4" || all[1].Value != "5.6.7.8" || !all[2].IsBlock() {
+		t.Errorf("GetAll() = %+v %+v %+v", all[0], all[1], all[2])
+	}
+	if got := s.String("ip"); got != "1.2.3.4" {
+		t.Errorf("String() = %q, want the first duplicate", got)
+	}
+
+	s.Set("ip", "9.9.9.9")
+	if len(s.GetAll("ip")) != 3 || s.String("ip") != "9.9.9.9" {
+		t.Errorf("Set() changed the duplicates: %+v", s.Children)
+	}
+	s.Add(&Node{Key: "ip", Value: "10.0.0.1"})
+	if len(s.GetAll("ip")) != 4 {
+		t.Errorf("Add() didn't keep the existing entries")
+	}
+}
+
+func TestParseConditionals(t *testing.T) {
+	root := mustParse(t, `"root"
+{
+	"win"		"x"		[$WIN32]
+	"linux"	[$LINUX]
+	{
+		"n"		"1"
+	}
+	"plain"		"y"
+	"empty"
+	{
+	}
+}`)
+	r := root.Get("root")
+	if got := r.Get("win").Cond; got != "$WIN32" {
+		t.Errorf("value conditional = %q", got)
+	}
+	if l := r.Get("linux"); l.Cond != "$LINUX" || l.String("n") != "1" {
+		t.Errorf("block conditional = %+v", l)
+	}
+	if got := r.Get("plain").Cond; got != "" {
+		t.Errorf("plain conditional = %q", got)
+	}
+	if e := r.Get("empty"); !e.IsBlock() || len(e.Children) != 0 {
+		t.Errorf("empty block = %+v", e)
+	}
+
+	out := string(Marshal(root))
+	for _, want := range []string{`"win"		"x" [$WIN32]`, `"linux" [$LINUX]`, "\t\"empty\"\n\t{\n\t}\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Marshal() missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	in := "\xef\xbb\xbf" + `// Written by the launcher
+// do not edit while Steam is running
+"filters"
+{
+	// servers the user joined
+	"history"
+	{
+		"0"		"1.2.3.4:2302"	// favourite
+		"1"		"5.6.7.8:2302"
+		// "2"		"retired:2302"
+	}	// end of history
+	"count"		"2"
+}
+// trailer
+`
+	root := mustParse(t, in)
+	f := root.Get("filters")
+	h := f.Get("history")
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"file header", f.Comments, []string{" Written by the launcher", " do not edit while Steam is running"}},
+		{"block comment", h.Comments, []string{" servers the user joined"}},
+		{"trailing on value", h.Get("0").LineComment, " favourite"},
+		{"no trailing", h.Get("1").LineComment, ""},
+		{"commented out entry", h.EndComments, []string{` "2"		"retired:2302"`}},
+		{"trailing on block", h.LineComment, " end of history"},
+		{"end of file", root.EndComments, []string{" trailer"}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %q, want %q", c.name, c.got, c.want)
+		}
+	}
+	if len(h.Children) != 2 {
+		t.Errorf("commented out entry was parsed: %+v", h.Children)
+	}
+
+	want := `// Written by the launcher
+// do not edit while Steam is running
+"filters"
+{
+	// servers the user joined
+	"history"
+	{
+		"0"		"1.2.3.4:2302"	// favourite
+		"1"		"5.6.7.8:2302"
+		// "2"		"retired:2302"
+	}	// end of history
+	"count"		"2"
+}
+// trailer
+`
+	out := string(Marshal(root))
+	if out != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", out, want)
+	}
+	if again := string(Marshal(mustParse(t, out))); again != out {
+		t.Errorf("second Marshal() differs:\n%s", again)
+	}
+}
+
+func TestCommentPlacement(t *testing.T) {
+	root := mustParse(t, `"a" { // after brace
+	"b" // between key and value
+	"1"
+	"c" "2" // on c
+	// own line, before d
+	"d" "3"
+}`)
+	a := root.Get("a")
+	if got := a.Get("b").Comments; !reflect.DeepEqual(got, []string{" after brace", " between key and value"}) {
+		t.Errorf("b comments = %q", got)
+	}
+	if got := a.Get("c").LineComment; got != " on c" {
+		t.Errorf("c line comment = %q", got)
+	}
+	if got := a.Get("d").Comments; !reflect.DeepEqual(got, []string{" own line, before d"}) {
+		t.Errorf("d comments = %q", got)
+	}
+	if a.Get("c").Comments != nil || a.Get("d").LineComment != "" {
+		t.Errorf("comment attached twice: c=%+v d=%+v", a.Get("c"), a.Get("d"))
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		line int
+	}{
+		{`"a" {`, 1},
+		{"\"a\" \"1\"\n}", 2},
+		{`"a" "b`, 1},
+		{`"a"`, 1},
+		{"\"a\"\n\"b\" \"c\" [$X", 2},
+		{`"a" [$WIN32] "b"`, 1},
+		{`{ "a" "b" }`, 1},
+	}
+	for _, tt := range tests {
+		_, err := ParseBytes([]byte(tt.in))
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("ParseBytes(%q) error = %v, want a ParseError", tt.in, err)
+			continue
+		}
+		if pe.Line != tt.line {
+			t.Errorf("ParseBytes(%q) error on line %d, want %d: %v", tt.in, pe.Line, tt.line, err)
+		}
+	}
+}
+
+type manifest struct {
+	AppState struct {
+		AppID      uint32 `vdf:"appid"`
+		Name       string `vdf:"name"`
+		SizeOnDisk int64
+		AutoUpdate bool   `vdf:"AutoUpdateBehavior"`
+		Ignored    string `vdf:"-"`
+		Depots     map[string]struct {
+			Manifest string `vdf:"manifest"`
+			Size     uint64 `vdf:"size"`
+		} `vdf:"InstalledDepots"`
+		Tags   []string    `vdf:"tag"`
+		Config *Node       `vdf:"UserConfig"`
+		Any    interface{} `vdf:"UserConfig"`
+	}
+}
+
+const manifestData = `"AppState"
+{
+	"appid"		"221100"
+	"name"		"DayZ"
+	"SizeOnDisk"		"16712482134"
+	"AutoUpdateBehavior"		"1"
+	"Ignored"		"x"
+	"tag"		"survival"
+	"tag"		"open world"
+	"InstalledDepots"
+	{
+		"221101"
+		{
+			"manifest"		"5463287469553427632"
+			"size"		"16712482134"
+		}
+	}
+	"UserConfig"
+	{
+		"language"		"english"
+	}
+}`
+
+func TestUnmarshal(t *testing.T) {
+	var m manifest
+	if err := Unmarshal([]byte(manifestData), &m); err != nil {
+		t.Fatal(err)
+	}
+	s := m.AppState
+	if s.AppID != 221100 || s.Name != "DayZ" || s.SizeOnDisk != 16712482134 || !s.AutoUpdate {
+		t.Errorf("scalars = %d %q %d %v", s.AppID, s.Name, s.SizeOnDisk, s.AutoUpdate)
+	}
+	if s.Ignored != "" {
+		t.Errorf("Ignored = %q, want it skipped", s.Ignored)
+	}
+	if d := s.Depots["221101"]; d.Manifest != "5463287469553427632" || d.Size != 16712482134 {
+		t.Errorf("Depots = %+v", s.Depots)
+	}
+	if !reflect.DeepEqual(s.Tags, []string{"survival", "open world"}) {
+		t.Errorf("Tags = %q", s.Tags)
+	}
+	if s.Config == nil || s.Config.String("language") != "english" {
+		t.Errorf("Config = %+v", s.Config)
+	}
+	if want := map[string]interface{}{"language": "english"}; !reflect.DeepEqual(s.Any, want) {
+		t.Errorf("Any = %v, want %v", s.Any, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"bad number", `"AppState" { "appid" "DayZ" }`},
+		{"value into struct", `"AppState" "1"`},
+		{"block into scalar", `"AppState" { "name" { "x" "1" } }`},
+		{"overflow", `"AppState" { "appid" "4294967296" }`},
+		{"parse error", `"AppState" {`},
+	}
+	for _, tt := range tests {
+		var m manifest
+		if err := Unmarshal([]byte(tt.in), &m); err == nil {
+			t.Errorf("%s: Unmarshal() succeeded: %+v", tt.name, m)
+		}
+	}
+
+	var m manifest
+	if err := Unmarshal([]byte(manifestData), m); err == nil {
+		t.Error("Unmarshal(non-pointer) succeeded")
+	}
+}
@@ -0,0 +1,238 @@
+package workshop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long cached details count as fresh
+	DefaultTTL = 6 * time.Hour
+
+	maxConcurrentBatches = 4
+)
+
+type cacheEntry struct {
+	Details   PublishedFileDetails `json:"details"`
+	FetchedAt time.Time            `json:"fetchedAt"`
+	// Descriptions are large and rarely needed, so they only live in memory
+	hasDescription bool
+}
+
+// Cache is a disk backed store of Workshop details. Lookups are served from
+// memory when fresh, refreshed from the Web API in concurrent batches when
+// stale, and fall back to stale data when Steam can't be reached.
+type Cache struct {
+	mu      sync.RWMutex
+	path    string
+	ttl     time.Duration
+	client  *http.Client
+	fetcher Fetcher
+	entries map[string]*cacheEntry
+
+	saveMu sync.Mutex
+}
+
+// NewCache loads the cache file at path (which may not exist yet)
+func NewCache(path string, client *http.Client, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		client:  client,
+		entries: make(map[string]*cacheEntry),
+	}
+	c.load()
+	return c
+}
+
+// SetFetcher replaces the Web API as the source of details, e.g. with the
+// Steam client. Passing nil restores the Web API.
+func (c *Cache) SetFetcher(f Fetcher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetcher = f
+}
+
+// Get returns cached details regardless of age, without touching the network
+func (c *Cache) Get(id string) (PublishedFileDetails, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	e, ok := c.entries[id]
+	if !ok {
+		return PublishedFileDetails{}, false
+	}
+	return e.Details, true
+}
+
+// Lookup returns details for ids in request order. Entries older than maxAge
+// (0 means the cache TTL) are refreshed. If the refresh fails, whatever is
+// cached is still returned along with the error.
+func (c *Cache) Lookup(ids []string, maxAge time.Duration) ([]PublishedFileDetails, error) {
+	return c.lookup(ids, maxAge, false)
+}
+
+// LookupWithDescriptions is Lookup, but also refetches entries whose
+// description isn't in memory.
+func (c *Cache) LookupWithDescriptions(ids []string, maxAge time.Duration) ([]PublishedFileDetails, error) {
+	return c.lookup(ids, maxAge, true)
+}
+
+func (c *Cache) lookup(ids []string, maxAge time.Duration, needDescription bool) ([]PublishedFileDetails, error) {
+	if maxAge <= 0 {
+		maxAge = c.ttl
+	}
+
+	now := time.Now()
+	var missing []string
+	seen := make(map[string]bool, len(ids))
+
+	c.mu.RLock()
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		e, ok := c.entries[id]
+		// Result 0 means the entry predates status tracking
+		if !ok || now.Sub(e.FetchedAt) > maxAge || e.Details.Result == 0 || (needDescription && !e.hasDescription) {
+			missing = append(missing, id)
+		}
+	}
+	c.mu.RUnlock()
+
+	var fetchErr error
+	if len(missing) > 0 {
+		fetchErr = c.fetch(missing)
+	}
+
+	c.mu.RLock()
+	result := make([]PublishedFileDetails, 0, len(ids))
+	for _, id := range ids {
+		if e, ok := c.entries[id]; ok {
+			result = append(result, e.Details)
+		}
+	}
+	c.mu.RUnlock()
+
+	return result, fetchErr
+}
+
+// fetch refreshes ids in BatchSize chunks, a few at a time
+func (c *Cache) fetch(ids []string) error {
+	c.mu.RLock()
+	fetcher := c.fetcher
+	c.mu.RUnlock()
+	if fetcher == nil {
+		fetcher = func(batch []string) ([]PublishedFileDetails, error) {
+			return FetchDetails(c.client, batch)
+		}
+	}
+
+	var batches [][]string
+	for start := 0; start < len(ids); start += BatchSize {
+		end := start + BatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batches = append(batches, ids[start:end])
+	}
+
+	var wg sync.WaitGroup
+	var errMu sync.Mutex
+	var errs []error
+	sem := make(chan struct{}, maxConcurrentBatches)
+
+	for _, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			details, err := fetcher(batch)
+			if err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+				return
+			}
+			c.store(details)
+		}(batch)
+	}
+	wg.Wait()
+
+	if err := c.save(); err != nil {
+		fmt.Printf("[Workshop] Failed to save cache: %v\n", err)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cache) store(details []PublishedFileDetails) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, d := range details {
+		if d.PublishedFileId == "" {
+			continue
+		}
+		c.entries[d.PublishedFileId] = &cacheEntry{Details: d, FetchedAt: now, hasDescription: true}
+	}
+}
+
+func (c *Cache) load() {
+	if c.path == "" {
+		return
+	}
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+	var entries map[string]*cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		fmt.Printf("[Workshop] Ignoring unreadable cache: %v\n", err)
+		return
+	}
+	// A file holding null (or null entries) must not replace the usable map
+	for id, e := range entries {
+		if e != nil {
+			c.entries[id] = e
+		}
+	}
+}
+
+func (c *Cache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	out := make(map[string]cacheEntry, len(c.entries))
+	for id, e := range c.entries {
+		entry := *e
+		entry.Details.Description = ""
+		out[id] = entry
+	}
+	c.mu.RUnlock()
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so a crash can't leave a half written cache
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,29 @@
+package workshop
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheLoadNull(t *testing.T) {
+	for _, data := range []string{`null`, `{"1559212036":null}`} {
+		path := filepath.Join(t.TempDir(), "workshop_cache.json")
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		c := NewCache(path, nil, time.Hour)
+		if _, ok := c.Get("1559212036"); ok {
+			t.Errorf("%s: Get() found an entry", data)
+		}
+		c.store([]PublishedFileDetails{{PublishedFileId: "1559212036", Title: "CF"}})
+		if d, ok := c.Get("1559212036"); !ok || d.Title != "CF" {
+			t.Errorf("%s: Get() after store = %+v, %t", data, d, ok)
+		}
+		if err := c.save(); err != nil {
+			t.Errorf("%s: save(): %v", data, err)
+		}
+	}
+}
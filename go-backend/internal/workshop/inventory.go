@@ -0,0 +1,140 @@
+package workshop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"dayz-launcher-go/internal/vdf"
+)
+
+// InventoryItem is a mod Steam has installed, as recorded on disk
+type InventoryItem struct {
+	ID          string `json:"id"`
+	Path        string `json:"path,omitempty"` // content folder, empty if it's missing
+	Size        uint64 `json:"size"`
+	TimeUpdated int64  `json:"timeUpdated"` // Workshop version that is installed
+	Manifest    string `json:"manifest,omitempty"`
+	Subscribed  bool   `json:"subscribed"`
+	// NeedsUpdate is set when Steam already knows of a newer manifest
+	NeedsUpdate bool `json:"needsUpdate"`
+}
+
+// Inventory is Steam's view of installed Workshop items, read from
+// appworkshop_<appid>.acf and the content folder. It works without the
+// Steam client running.
+type Inventory struct {
+	AppID      uint32                    `json:"appId"`
+	ContentDir string                    `json:"contentDir"`
+	Items      map[string]*InventoryItem `json:"items"`
+	ReadAt     time.Time                 `json:"readAt"`
+}
+
+type acfWorkshop struct {
+	AppWorkshop struct {
+		Installed map[string]acfInstalled `vdf:"WorkshopItemsInstalled"`
+		Details   map[string]acfDetails   `vdf:"WorkshopItemDetails"`
+	}
+}
+
+type acfInstalled struct {
+	Size        uint64 `vdf:"size"`
+	TimeUpdated int64  `vdf:"timeupdated"`
+	Manifest    string `vdf:"manifest"`
+}
+
+type acfDetails struct {
+	Manifest       string `vdf:"manifest"`
+	TimeUpdated    int64  `vdf:"timeupdated"`
+	SubscribedBy   uint64 `vdf:"subscribedby"`
+	LatestManifest string `vdf:"latest_manifest"`
+}
+
+// LoadInventory reads the inventory from a library's steamapps/workshop
+// folder. Folders in the content directory that the manifest doesn't know
+// about are included with only their path set.
+func LoadInventory(workshopDir string, appID uint32) (*Inventory, error) {
+	appIDStr := strconv.FormatUint(uint64(appID), 10)
+	inv := &Inventory{
+		AppID:      appID,
+		ContentDir: filepath.Join(workshopDir, "content", appIDStr),
+		Items:      make(map[string]*InventoryItem),
+		ReadAt:     time.Now(),
+	}
+
+	acfPath := filepath.Join(workshopDir, fmt.Sprintf("appworkshop_%s.acf", appIDStr))
+	data, acfErr := os.ReadFile(acfPath)
+	if acfErr == nil {
+		var acf acfWorkshop
+		if err := vdf.Unmarshal(data, &acf); err != nil {
+			return nil, fmt.Errorf("%s: %w", acfPath, err)
+		}
+		for id, in := range acf.AppWorkshop.Installed {
+			item := &InventoryItem{
+				ID:          id,
+				Size:        in.Size,
+				TimeUpdated: in.TimeUpdated,
+				Manifest:    in.Manifest,
+			}
+			if d, ok := acf.AppWorkshop.Details[id]; ok {
+				item.Subscribed = d.SubscribedBy != 0
+				item.NeedsUpdate = d.LatestManifest != "" && d.LatestManifest != in.Manifest
+			}
+			inv.Items[id] = item
+		}
+	}
+
+	entries, dirErr := os.ReadDir(inv.ContentDir)
+	if acfErr != nil && dirErr != nil {
+		return nil, acfErr
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := strconv.ParseUint(e.Name(), 10, 64); err != nil {
+			continue
+		}
+		item, ok := inv.Items[e.Name()]
+		if !ok {
+			item = &InventoryItem{ID: e.Name()}
+			inv.Items[e.Name()] = item
+		}
+		item.Path = filepath.Join(inv.ContentDir, e.Name())
+	}
+	return inv, nil
+}
+
+// Get returns the item for id, if Steam has it installed
+func (inv *Inventory) Get(id string) (*InventoryItem, bool) {
+	item, ok := inv.Items[id]
+	return item, ok
+}
+
+// InstalledIDs returns the IDs of items present on disk, sorted
+func (inv *Inventory) InstalledIDs() []string {
+	ids := make([]string, 0, len(inv.Items))
+	for id, item := range inv.Items {
+		if item.Path != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// LikelyUpToDate reports whether the installed copy matches the Workshop.
+// latestUpdate is the Workshop time_updated if known (0 otherwise); without
+// it, only Steam's own NeedsUpdate hint is used.
+func (item *InventoryItem) LikelyUpToDate(latestUpdate int64) bool {
+	if item.Path == "" || item.NeedsUpdate {
+		return false
+	}
+	if latestUpdate > 0 && item.TimeUpdated > 0 {
+		return item.TimeUpdated >= latestUpdate
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package workshop
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"dayz-launcher-go/internal/dayz"
+)
+
+const (
+	detailsURL = "https://api.steampowered.com/ISteamRemoteStorage/GetPublishedFileDetails/v1/"
+
+	// BatchSize is the most IDs sent in a single GetPublishedFileDetails call
+	BatchSize = 100
+)
+
+// EResult values the Web API reports per item
+const (
+	resultOK           = 1
+	resultFileNotFound = 9
+	resultAccessDenied = 15
+)
+
+// ERemoteStoragePublishedFileVisibility
+const (
+	visibilityPublic      = 0
+	visibilityFriendsOnly = 1
+	visibilityPrivate     = 2
+	visibilityUnlisted    = 3
+)
+
+// ItemStatus classifies whether a Workshop item can actually be downloaded
+type ItemStatus string
+
+const (
+	StatusOK       ItemStatus = "ok"
+	StatusRemoved  ItemStatus = "removed"
+	StatusHidden   ItemStatus = "hidden"
+	StatusBanned   ItemStatus = "banned"
+	StatusWrongApp ItemStatus = "wrong_app"
+	StatusUnknown  ItemStatus = "unknown"
+)
+
+// Usable reports whether Steam will let the user download the item
+func (s ItemStatus) Usable() bool {
+	return s == StatusOK
+}
+
+// Reason is a user facing explanation for an unusable item
+func (s ItemStatus) Reason() string {
+	switch s {
+	case StatusRemoved:
+		return "removed from the Workshop"
+	case StatusHidden:
+		return "private or hidden by its author"
+	case StatusBanned:
+		return "banned by Steam"
+	case StatusWrongApp:
+		return "not a DayZ Workshop item"
+	case StatusUnknown:
+		return "status could not be determined"
+	}
+	return ""
+}
+
+// Tag is a Workshop tag as returned by the Web API
+type Tag struct {
+	Tag string `json:"tag"`
+}
+
+// PublishedFileDetails is a Workshop item from ISteamRemoteStorage/GetPublishedFileDetails
+type PublishedFileDetails struct {
+	PublishedFileId string `json:"publishedfileid"`
+	Title           string `json:"title"`
+	FileSize        string `json:"file_size"`
+	TimeUpdated     int64  `json:"time_updated"`
+	Creator         string `json:"creator"`
+	PreviewUrl      string `json:"preview_url"`
+	Description     string `json:"description,omitempty"`
+	Tags            []Tag  `json:"tags,omitempty"`
+	Result          int    `json:"result"`
+	Banned          int    `json:"banned"`
+	Visibility      int    `json:"visibility"`
+	ConsumerAppID   int    `json:"consumer_app_id"`
+	// Required items; only the Steam client reports these
+	Children []string `json:"children,omitempty"`
+}
+
+// Status classifies the item from the result, banned, visibility and consumer_app_id fields
+func (d *PublishedFileDetails) Status() ItemStatus {
+	switch d.Result {
+	case resultOK:
+	case resultFileNotFound:
+		return StatusRemoved
+	case resultAccessDenied:
+		return StatusHidden
+	default:
+		return StatusUnknown
+	}
+
+	switch {
+	case d.Banned != 0:
+		return StatusBanned
+	case d.ConsumerAppID != 0 && d.ConsumerAppID != dayz.AppID:
+		return StatusWrongApp
+	case d.Visibility == visibilityFriendsOnly || d.Visibility == visibilityPrivate:
+		return StatusHidden
+	}
+	return StatusOK
+}
+
+// Size returns the file size in bytes (the API sends it as a string)
+func (d *PublishedFileDetails) Size() uint64 {
+	n, _ := strconv.ParseUint(d.FileSize, 10, 64)
+	return n
+}
+
+// ResponseContainer is the Web API envelope
+type ResponseContainer struct {
+	Response struct {
+		PublishedFileDetails []PublishedFileDetails `json:"publishedfiledetails"`
+	} `json:"response"`
+}
+
+// Fetcher retrieves details for up to BatchSize ids
+type Fetcher func(ids []string) ([]PublishedFileDetails, error)
+
+// FetchDetails performs one Web API request. Callers should keep ids within BatchSize.
+func FetchDetails(client *http.Client, ids []string) ([]PublishedFileDetails, error) {
+	form := url.Values{}
+	form.Add("itemcount", fmt.Sprintf("%d", len(ids)))
+	for i, id := range ids {
+		form.Add(fmt.Sprintf("publishedfileids[%d]", i), id)
+	}
+
+	resp, err := client.PostForm(detailsURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Error %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ResponseContainer
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("JSON Parse Error: %v", err)
+	}
+	return data.Response.PublishedFileDetails, nil
+}
@@ -0,0 +1,38 @@
+package workshop
+
+import "testing"
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		details PublishedFileDetails
+		want    ItemStatus
+	}{
+		{"ok", PublishedFileDetails{Result: resultOK, ConsumerAppID: 221100}, StatusOK},
+		{"ok without app id", PublishedFileDetails{Result: resultOK}, StatusOK},
+		{"unlisted", PublishedFileDetails{Result: resultOK, Visibility: visibilityUnlisted}, StatusOK},
+		{"removed", PublishedFileDetails{Result: resultFileNotFound}, StatusRemoved},
+		{"access denied", PublishedFileDetails{Result: resultAccessDenied}, StatusHidden},
+		{"private", PublishedFileDetails{Result: resultOK, Visibility: visibilityPrivate}, StatusHidden},
+		{"friends only", PublishedFileDetails{Result: resultOK, Visibility: visibilityFriendsOnly}, StatusHidden},
+		{"banned", PublishedFileDetails{Result: resultOK, Banned: 1, ConsumerAppID: 221100}, StatusBanned},
+		{"banned beats private", PublishedFileDetails{Result: resultOK, Banned: 1, Visibility: visibilityPrivate}, StatusBanned},
+		{"incompatible app", PublishedFileDetails{Result: resultOK, ConsumerAppID: 107410}, StatusWrongApp},
+		{"unknown result", PublishedFileDetails{Result: 2}, StatusUnknown},
+		{"no result", PublishedFileDetails{}, StatusUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.details.Status()
+			if got != tt.want {
+				t.Fatalf("Status() = %q, want %q", got, tt.want)
+			}
+			if got.Usable() != (got == StatusOK) {
+				t.Errorf("Usable() = %t", got.Usable())
+			}
+			if reason := got.Reason(); (reason == "") != got.Usable() {
+				t.Errorf("Reason() = %q", reason)
+			}
+		})
+	}
+}
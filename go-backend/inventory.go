@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamlocate"
+	"dayz-launcher-go/internal/steamworks"
+	"dayz-launcher-go/internal/workshop"
+)
+
+// The inventory is polled along with downloads, so keep re-reads cheap
+const inventoryMaxAge = 10 * time.Second
+
+// offlineInventory reads installed mods from disk for when Steam isn't
+// running. Returns nil if the Steam install can't be found.
+func (a *App) offlineInventory() *workshop.Inventory {
+	a.invMu.Lock()
+	defer a.invMu.Unlock()
+
+	if a.inventory != nil && time.Since(a.inventory.ReadAt) < inventoryMaxAge {
+		return a.inventory
+	}
+
+	steam, err := steamlocate.Locate()
+	if err != nil {
+		return nil
+	}
+	dir := steam.WorkshopDir(dayz.AppID)
+	if dir == "" {
+		return nil
+	}
+	inv, err := workshop.LoadInventory(dir, dayz.AppID)
+	if err != nil {
+		fmt.Printf("[App] Could not read offline mod inventory: %v\n", err)
+		return nil
+	}
+	a.inventory = inv
+	return inv
+}
+
+// offlineModStatus is CheckMod without Steam: installed mods are reported
+// with a best guess of whether they're current, using cached Workshop data
+func (a *App) offlineModStatus(modId string, verify bool) map[string]interface{} {
+	res := map[string]interface{}{"success": true, "status": "unknown", "progress": 0.0, "stateFlags": 0, "offline": true}
+
+	inv := a.offlineInventory()
+	if inv == nil {
+		return res
+	}
+	item, ok := inv.Get(modId)
+	if !ok || item.Path == "" || !steamworks.IsModFolderValid(item.Path) {
+		return res
+	}
+
+	status := "installed"
+	if verify && !dayz.VerifyModIntegrity(context.Background(), item.Path).Valid {
+		status = "corrupt"
+	}
+	res["status"] = status
+	res["likelyUpToDate"] = item.LikelyUpToDate(a.cachedModUpdated(modId))
+	return res
+}
+
+// offlineDownloads mirrors GetActiveDownloads while Steam is closed. Nothing
+// can download, so only mods Steam has flagged for an update are listed.
+func (a *App) offlineDownloads() interface{} {
+	var pending []map[string]interface{}
+	installed := 0
+
+	if inv := a.offlineInventory(); inv != nil {
+		for _, id := range inv.InstalledIDs() {
+			installed++
+			item, _ := inv.Get(id)
+			if item.LikelyUpToDate(a.cachedModUpdated(id)) {
+				continue
+			}
+			name, ok := a.cachedModTitle(id)
+			if !ok {
+				name = fmt.Sprintf("Mod %s", id)
+			}
+			pending = append(pending, map[string]interface{}{
+				"id":       id,
+				"name":     name,
+				"status":   dlStatusQueued,
+				"progress": 0.0,
+				"current":  0,
+				"total":    item.Size,
+				"speed":    0.0,
+				"eta":      -1,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"type": "download-update",
+		"data": pending,
+		"meta": map[string]interface{}{
+			"connected": false,
+			"offline":   true,
+			"installed": installed,
+			"speed":     0.0,
+			"eta":       -1,
+		},
+	}
+}
+
+// GetInstalledMods lists mods on disk with a freshness guess. It works with
+// or without Steam running.
+func (a *App) GetInstalledMods() (interface{}, error) {
+	inv := a.offlineInventory()
+	if inv == nil {
+		return map[string]interface{}{"success": false, "error": "Could not find the Steam workshop folder"}, nil
+	}
+
+	mods := make([]map[string]interface{}, 0, len(inv.Items))
+	for _, id := range inv.InstalledIDs() {
+		item, _ := inv.Get(id)
+		name, _ := a.cachedModTitle(id)
+		mods = append(mods, map[string]interface{}{
+			"id":             id,
+			"name":           name,
+			"path":           item.Path,
+			"size":           item.Size,
+			"timeUpdated":    item.TimeUpdated,
+			"subscribed":     item.Subscribed,
+			"likelyUpToDate": item.LikelyUpToDate(a.cachedModUpdated(id)),
+		})
+	}
+	return map[string]interface{}{"success": true, "mods": mods, "offline": !a.steam.IsInitialized()}, nil
+}
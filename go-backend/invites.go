@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamworks"
+	"dayz-launcher-go/internal/urlscheme"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Deep links look like hanlauncher://join?ip=1.2.3.4&port=2302&qport=27016&name=...
+const deepLinkScheme = "hanlauncher"
+
+// ServerInvite is a server to invite friends to, or one we were invited to
+type ServerInvite struct {
+	From       steamid.ID `json:"from,omitempty"` // inviter, incoming only
+	IP         string     `json:"ip"`
+	GamePort   int        `json:"gamePort"`
+	QueryPort  int        `json:"queryPort,omitempty"`
+	ServerName string     `json:"serverName,omitempty"`
+}
+
+// registerDeepLinks makes the OS open hanlauncher:// links with this
+// launcher. It runs on every start so a moved install keeps working.
+func registerDeepLinks() {
+	exe, err := urlscheme.Executable()
+	if err == nil {
+		err = urlscheme.Register(deepLinkScheme, "HAN Launcher", exe)
+	}
+	if err != nil && !errors.Is(err, urlscheme.ErrUnsupported) {
+		fmt.Printf("[App] Could not register %s:// links: %v\n", deepLinkScheme, err)
+	}
+}
+
+// inviteLink builds the launcher deep link for a server
+func inviteLink(s ServerInvite) string {
+	q := url.Values{}
+	q.Set("ip", s.IP)
+	q.Set("port", strconv.Itoa(s.GamePort))
+	if s.QueryPort != 0 {
+		q.Set("qport", strconv.Itoa(s.QueryPort))
+	}
+	if s.ServerName != "" {
+		q.Set("name", s.ServerName)
+	}
+	return deepLinkScheme + "://join?" + q.Encode()
+}
+
+// parseInviteLink reads a deep link made by inviteLink
+func parseInviteLink(link string) (ServerInvite, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != deepLinkScheme || (u.Host != "join" && u.Opaque != "join") {
+		return ServerInvite{}, false
+	}
+	q := u.Query()
+	inv := ServerInvite{IP: q.Get("ip"), ServerName: q.Get("name")}
+	inv.GamePort, _ = strconv.Atoi(q.Get("port"))
+	inv.QueryPort, _ = strconv.Atoi(q.Get("qport"))
+	if inv.IP == "" || inv.GamePort <= 0 || inv.GamePort > 65535 {
+		return ServerInvite{}, false
+	}
+	return inv, true
+}
+
+// parseInviteArgs finds an invite in command line arguments: a deep link,
+// or the -connect/-port pair Steam passes for a rich presence join
+func parseInviteArgs(args []string) (ServerInvite, bool) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, deepLinkScheme+":") {
+			return parseInviteLink(arg)
+		}
+	}
+	if ip, port, ok := parseConnectString(strings.Join(args, " ")); ok {
+		return ServerInvite{IP: ip, GamePort: port}, true
+	}
+	return ServerInvite{}, false
+}
+
+// InviteFriends invites friends to a server, by default the one we're
+// playing on. Steam game invites are tried first; friends Steam won't
+// invite (e.g. not running DayZ) get a chat message with the address and
+// a deep link, for those who have the launcher.
+func (a *App) InviteFriends(steamIds []string, server ServerInvite) (interface{}, error) {
+	if !a.steam.IsInitialized() {
+		return map[string]interface{}{"success": false, "error": "Steam not initialized"}, nil
+	}
+	if server.IP == "" {
+		a.sessionMu.Lock()
+		if a.session != nil {
+			server = *a.session
+		}
+		a.sessionMu.Unlock()
+	}
+	if server.IP == "" || server.GamePort == 0 {
+		return map[string]interface{}{"success": false, "error": "No server to invite to"}, nil
+	}
+
+	connect := connectString(server.IP, server.GamePort)
+	addr := fmt.Sprintf("%s:%d", server.IP, server.GamePort)
+	name := addr
+	message := fmt.Sprintf("Join me on %s - %s", addr, inviteLink(server))
+	if server.ServerName != "" {
+		name = server.ServerName
+		message = fmt.Sprintf("Join me on %s (%s) - %s", name, addr, inviteLink(server))
+	}
+
+	results := make(map[string]string, len(steamIds))
+	sent := 0
+	for _, idStr := range steamIds {
+		id, err := steamid.Parse(idStr)
+		if err != nil {
+			results[idStr] = "invalid"
+			continue
+		}
+		switch {
+		case a.steam.InviteUserToGame(id.Uint64(), connect):
+			results[idStr] = "invite"
+		case a.steam.SendFriendMessage(id.Uint64(), message):
+			results[idStr] = "chat"
+		default:
+			results[idStr] = "failed"
+			continue
+		}
+		sent++
+	}
+	fmt.Printf("[App] Invited %d/%d friend(s) to %s\n", sent, len(steamIds), name)
+
+	return map[string]interface{}{"success": sent > 0, "sent": sent, "results": results}, nil
+}
+
+// receiveInvite holds on to an incoming invite until the frontend accepts
+// or reads it, and announces it
+func (a *App) receiveInvite(inv ServerInvite) {
+	fmt.Printf("[App] Invite received: %s:%d\n", inv.IP, inv.GamePort)
+	a.inviteMu.Lock()
+	a.pendingInvite = &inv
+	a.inviteMu.Unlock()
+	a.emit("invite-received", inv)
+}
+
+// onSecondInstance handles deep links opened while the launcher is running
+func (a *App) onSecondInstance(data options.SecondInstanceData) {
+	if a.ctx != nil {
+		runtime.WindowUnminimise(a.ctx)
+		runtime.WindowShow(a.ctx)
+	}
+	if inv, ok := parseInviteArgs(data.Args); ok {
+		a.receiveInvite(inv)
+	}
+}
+
+// GetPendingInvite returns the invite that started (or was sent to) the
+// launcher, if any, without consuming it
+func (a *App) GetPendingInvite() interface{} {
+	a.inviteMu.Lock()
+	defer a.inviteMu.Unlock()
+	if a.pendingInvite == nil {
+		return map[string]interface{}{"success": true, "invite": nil}
+	}
+	return map[string]interface{}{"success": true, "invite": *a.pendingInvite}
+}
+
+// DismissInvite drops the pending invite
+func (a *App) DismissInvite() {
+	a.inviteMu.Lock()
+	a.pendingInvite = nil
+	a.inviteMu.Unlock()
+}
+
+// AcceptInvite joins the pending invite's server with the launch settings
+// in req, verifying and downloading its mods first (see JoinWhenReady)
+func (a *App) AcceptInvite(req JoinRequest) (interface{}, error) {
+	a.inviteMu.Lock()
+	inv := a.pendingInvite
+	a.pendingInvite = nil
+	a.inviteMu.Unlock()
+	if inv == nil {
+		return map[string]interface{}{"success": false, "error": "No pending invite"}, nil
+	}
+	if req.ServerName == "" {
+		req.ServerName = inv.ServerName
+	}
+	return a.joinByGamePort(inv.IP, inv.GamePort, inv.QueryPort, req)
+}
+
+// steamJoinRequested handles a join accepted through the Steam overlay or
+// friends list while the launcher is running
+func (a *App) steamJoinRequested(ev steamworks.GameRichPresenceJoinRequested) {
+	inv, ok := parseInviteArgs(strings.Fields(ev.Connect))
+	if !ok {
+		fmt.Printf("[App] Unrecognised join request: %q\n", ev.Connect)
+		return
+	}
+	inv.From = steamid.ID(ev.SteamID)
+	a.receiveInvite(inv)
+}
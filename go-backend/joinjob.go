@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"dayz-launcher-go/internal/dayz"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Join job phases, reported through the "join-status" event
+const (
+	joinPhaseVerifying   = "verifying"
+	joinPhaseDownloading = "downloading"
+	joinPhaseValidating  = "validating"
+	joinPhaseLaunching   = "launching"
+	joinPhaseDone        = "done"
+	joinPhaseFailed      = "failed"
+	joinPhaseCancelled   = "cancelled"
+)
+
+const (
+	joinPollInterval = 2 * time.Second
+	// Validation failures send the job back to downloading at most this often
+	joinMaxValidationRounds = 3
+)
+
+// JoinRequest is everything needed to launch into a server once mods are ready
+type JoinRequest struct {
+	IP             string `json:"ip"`
+	QueryPort      int    `json:"queryPort"`
+	Name           string `json:"name"`
+	LaunchParams   string `json:"launchParams"`
+	DiscordEnabled bool   `json:"discordEnabled"`
+	ServerName     string `json:"serverName"`
+}
+
+// JoinStatus is the last state of the join job
+type JoinStatus struct {
+	Active     bool        `json:"active"`
+	Phase      string      `json:"phase"`
+	IP         string      `json:"ip"`
+	QueryPort  int         `json:"queryPort"`
+	ServerName string      `json:"serverName,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Progress   interface{} `json:"progress,omitempty"`
+}
+
+type joinJob struct {
+	req    JoinRequest
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu     sync.Mutex
+	status JoinStatus
+}
+
+// -- JOIN METHODS --
+
+// JoinWhenReady verifies the server, downloads whatever is missing and
+// launches the game as soon as every mod is installed and valid.
+// Starting a new join cancels the previous one.
+func (a *App) JoinWhenReady(req JoinRequest) (interface{}, error) {
+	if req.IP == "" || req.QueryPort <= 0 {
+		return map[string]interface{}{"success": false, "error": "Invalid server address"}, nil
+	}
+
+	a.CancelJoin()
+
+	parent := a.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	job := &joinJob{
+		req:    req,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		status: JoinStatus{Active: true, IP: req.IP, QueryPort: req.QueryPort, ServerName: req.ServerName},
+	}
+
+	a.joinMu.Lock()
+	a.join = job
+	a.joinMu.Unlock()
+
+	go func() {
+		defer close(job.done)
+		a.runJoin(ctx, job)
+
+		a.joinMu.Lock()
+		job.cancel = nil
+		a.joinMu.Unlock()
+		cancel()
+	}()
+
+	return map[string]interface{}{"success": true}, nil
+}
+
+// CancelJoin stops the pending join, if any. The job is kept so
+// GetJoinStatus still reports it as cancelled.
+func (a *App) CancelJoin() (interface{}, error) {
+	a.joinMu.Lock()
+	job := a.join
+	var cancel context.CancelFunc
+	if job != nil {
+		cancel, job.cancel = job.cancel, nil
+	}
+	a.joinMu.Unlock()
+
+	if cancel == nil {
+		return map[string]interface{}{"success": true, "cancelled": false}, nil
+	}
+	cancel()
+	<-job.done
+	return map[string]interface{}{"success": true, "cancelled": true}, nil
+}
+
+// GetJoinStatus returns the state of the pending or last finished join job
+func (a *App) GetJoinStatus() JoinStatus {
+	a.joinMu.Lock()
+	job := a.join
+	a.joinMu.Unlock()
+
+	if job == nil {
+		return JoinStatus{}
+	}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.status
+}
+
+func (a *App) setJoinPhase(job *joinJob, phase, message string) {
+	job.mu.Lock()
+	job.status.Phase = phase
+	job.status.Message = message
+	job.status.Progress = a.joinProgress()
+	switch phase {
+	case joinPhaseDone, joinPhaseFailed, joinPhaseCancelled:
+		job.status.Active = false
+	}
+	if phase == joinPhaseFailed {
+		job.status.Error = message
+	}
+	status := job.status
+	job.mu.Unlock()
+
+	a.emit("join-status", status)
+}
+
+// joinCancelled marks the job cancelled if ctx is done
+func (a *App) joinCancelled(ctx context.Context, job *joinJob) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	a.setJoinPhase(job, joinPhaseCancelled, "Join cancelled")
+	return true
+}
+
+func (a *App) runJoin(ctx context.Context, job *joinJob) {
+	req := job.req
+
+	// 1. Verify the server and work out what it needs
+	a.setJoinPhase(job, joinPhaseVerifying, "Querying server mods")
+	res := a.verifyServer(req.IP, req.QueryPort)
+	if a.joinCancelled(ctx, job) {
+		return
+	}
+	if !res.Success {
+		a.setJoinPhase(job, joinPhaseFailed, "Could not verify server: "+res.Error)
+		return
+	}
+	if res.Blocked {
+		a.setJoinPhase(job, joinPhaseFailed, res.BlockReason)
+		return
+	}
+	if req.ServerName == "" {
+		req.ServerName = res.Name
+	}
+
+	// Local folders satisfy mods without touching the Workshop
+	locals := a.GetLocalMods()
+	var entries, workshopIDs []string
+	for _, m := range res.Mods {
+		if local, _ := localModFor(locals, m); local != nil {
+			entries = append(entries, local.Path)
+			continue
+		}
+		entries = append(entries, m.WorkshopID)
+		workshopIDs = append(workshopIDs, m.WorkshopID)
+	}
+
+	if a.joinCancelled(ctx, job) {
+		return
+	}
+	a.downloads.SetPriority(workshopIDs)
+
+	for round := 1; ; round++ {
+		// 2. Wait until Steam has everything on disk. After a failed validation,
+		// give Steam a moment to pick up the re-issued downloads first.
+		if !a.waitForMods(ctx, job, workshopIDs, round > 1) {
+			return
+		}
+
+		// 3. Make sure what's on disk is actually usable
+		a.setJoinPhase(job, joinPhaseValidating, fmt.Sprintf("Validating %d mod(s)", len(workshopIDs)))
+		broken := a.validateJoinMods(ctx, workshopIDs)
+		if a.joinCancelled(ctx, job) {
+			return
+		}
+		if len(broken) == 0 {
+			break
+		}
+		if round >= joinMaxValidationRounds {
+			a.setJoinPhase(job, joinPhaseFailed, fmt.Sprintf("%d mod(s) still failing verification after re-download", len(broken)))
+			return
+		}
+		fmt.Printf("[Join] %d mod(s) failed validation, re-downloading\n", len(broken))
+		a.downloads.Require(broken, true)
+	}
+
+	// 4. Launch
+	if a.joinCancelled(ctx, job) {
+		return
+	}
+	a.setJoinPhase(job, joinPhaseLaunching, "Launching DayZ")
+	out, err := a.LaunchGame(req.IP, res.GamePort, entries, req.Name, req.LaunchParams, req.DiscordEnabled, req.ServerName)
+	if err != nil {
+		a.setJoinPhase(job, joinPhaseFailed, "Launch failed: "+err.Error())
+		return
+	}
+	if m, ok := out.(map[string]interface{}); ok && m["success"] == false {
+		msg, _ := m["error"].(string)
+		a.setJoinPhase(job, joinPhaseFailed, "Launch refused: "+msg)
+		return
+	}
+	a.setJoinPhase(job, joinPhaseDone, "Game launched")
+}
+
+// waitForMods blocks until every mod is installed and up to date. Steam going
+// away is not fatal: the job waits and re-issues the downloads on reconnect.
+func (a *App) waitForMods(ctx context.Context, job *joinJob, modIds []string, settle bool) bool {
+	if len(modIds) == 0 {
+		return true
+	}
+
+	ticker := time.NewTicker(joinPollInterval)
+	defer ticker.Stop()
+
+	steamWasUp := true
+	for {
+		if settle {
+			settle = false
+		} else if !a.steam.IsInitialized() {
+			if steamWasUp {
+				a.setJoinPhase(job, joinPhaseDownloading, "Waiting for Steam to reconnect")
+			}
+			steamWasUp = false
+		} else {
+			if !steamWasUp {
+				fmt.Println("[Join] Steam reconnected, re-queueing downloads")
+				a.downloads.SetPriority(modIds)
+				steamWasUp = true
+			}
+
+			pending, failed := 0, 0
+			for _, dl := range a.downloads.Snapshot() {
+				if dl.Priority && dl.Status == dlStatusFailed {
+					failed++
+				}
+			}
+			for _, id := range modIds {
+				state := a.steam.GetItemState(id)
+				if state&itemStateInstalled == 0 || state&(itemStateNeedsUpdate|itemStateDownloading|itemStateDownloadPending) != 0 {
+					pending++
+				}
+			}
+
+			if failed > 0 {
+				a.setJoinPhase(job, joinPhaseFailed, fmt.Sprintf("%d mod(s) failed to download", failed))
+				return false
+			}
+			if pending == 0 {
+				return true
+			}
+			a.setJoinPhase(job, joinPhaseDownloading, "Downloading required mods")
+		}
+
+		select {
+		case <-ctx.Done():
+			a.setJoinPhase(job, joinPhaseCancelled, "Join cancelled")
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateJoinMods returns the mods that are stale or fail the integrity scan,
+// for the caller to re-queue. It stops early once ctx is done.
+func (a *App) validateJoinMods(ctx context.Context, modIds []string) []string {
+	var broken []string
+	seen := map[string]bool{}
+
+	if results, err := a.findOutdatedMods(modIds, false); err == nil {
+		for _, r := range results {
+			if r.Outdated {
+				broken = append(broken, r.ModID)
+				seen[r.ModID] = true
+			}
+		}
+	}
+
+	for _, id := range modIds {
+		if ctx.Err() != nil {
+			return broken
+		}
+		if seen[id] {
+			continue
+		}
+		path := a.steam.ResolveModPath(id)
+		if path == "" || !dayz.VerifyModIntegrity(ctx, path).Valid {
+			broken = append(broken, id)
+		}
+	}
+	return broken
+}
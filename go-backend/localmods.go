@@ -0,0 +1,195 @@
+package main
+
+import (
+	"dayz-launcher-go/internal/dayz"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// -- LOCAL MOD METHODS --
+
+var workshopIDRe = regexp.MustCompile(`^\d+$`)
+
+// Verified servers remembered for launches; the oldest are forgotten past this
+const maxServerModLists = 256
+
+func localModsFile() string {
+	dir := ensureConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "local_mods.json")
+}
+
+func loadLocalMods() []dayz.LocalMod {
+	mods := []dayz.LocalMod{}
+	path := localModsFile()
+	if path == "" {
+		return mods
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mods
+	}
+	if err := json.Unmarshal(data, &mods); err != nil {
+		fmt.Printf("[App] Failed to parse local mods: %v\n", err)
+	}
+	return mods
+}
+
+func saveLocalMods(mods []dayz.LocalMod) error {
+	path := localModsFile()
+	if path == "" {
+		return fmt.Errorf("no config dir")
+	}
+	data, err := json.MarshalIndent(mods, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// GetLocalMods returns the registered local mod folders
+func (a *App) GetLocalMods() []dayz.LocalMod {
+	a.localModsMu.Lock()
+	defer a.localModsMu.Unlock()
+	return loadLocalMods()
+}
+
+// AddLocalMod registers an unpacked @Mod folder after checking it has usable addons
+func (a *App) AddLocalMod(path string) (interface{}, error) {
+	mod, err := dayz.NewLocalMod(path)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	a.localModsMu.Lock()
+	defer a.localModsMu.Unlock()
+
+	mods := loadLocalMods()
+	replaced := false
+	for i := range mods {
+		if filepath.Clean(mods[i].Path) == mod.Path {
+			mods[i] = *mod
+			replaced = true
+		}
+	}
+	if !replaced {
+		mods = append(mods, *mod)
+	}
+
+	if err := saveLocalMods(mods); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	fmt.Printf("[App] Registered local mod %s (%s)\n", mod.Name, mod.Path)
+	return map[string]interface{}{"success": true, "mod": mod}, nil
+}
+
+// RemoveLocalMod unregisters a local mod folder. Files on disk are left alone.
+func (a *App) RemoveLocalMod(path string) (interface{}, error) {
+	a.localModsMu.Lock()
+	defer a.localModsMu.Unlock()
+
+	mods := loadLocalMods()
+	kept := mods[:0]
+	for _, m := range mods {
+		if filepath.Clean(m.Path) != filepath.Clean(path) {
+			kept = append(kept, m)
+		}
+	}
+	if err := saveLocalMods(kept); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	return map[string]interface{}{"success": true, "removed": len(mods) - len(kept)}, nil
+}
+
+// MatchLocalMods matches a server's mod list against the registered local folders
+func (a *App) MatchLocalMods(serverMods []dayz.Mod) []dayz.LocalModMatch {
+	locals := a.GetLocalMods()
+	matches := make([]dayz.LocalModMatch, 0, len(serverMods))
+	for _, m := range serverMods {
+		local, by := localModFor(locals, m)
+		matches = append(matches, dayz.LocalModMatch{Mod: m, LocalMod: local, MatchBy: by})
+	}
+	return matches
+}
+
+// localModFor decides whether a registered local folder stands in for a
+// server mod. Verification, joins and launches all go through it so they
+// can't disagree. Folders that no longer validate don't count.
+func localModFor(locals []dayz.LocalMod, m dayz.Mod) (*dayz.LocalMod, string) {
+	local, by := dayz.MatchLocalMod(locals, m)
+	if local == nil {
+		return nil, ""
+	}
+	if err := dayz.ValidateLocalMod(local.Path); err != nil {
+		fmt.Printf("[App] Local copy of %s unusable: %v\n", local.Name, err)
+		return nil, ""
+	}
+	return local, by
+}
+
+// rememberServerMods keeps a verified server's mod list, so a launch that
+// only gets Workshop IDs can still match local folders by name
+func (a *App) rememberServerMods(ip string, gamePort int, mods []dayz.Mod) {
+	a.serverModsMu.Lock()
+	defer a.serverModsMu.Unlock()
+	if a.serverMods == nil || len(a.serverMods) >= maxServerModLists {
+		a.serverMods = make(map[string][]dayz.Mod)
+	}
+	a.serverMods[fmt.Sprintf("%s:%d", ip, gamePort)] = mods
+}
+
+// serverMod returns the server's entry for a Workshop ID, as far as the
+// last verification knows it
+func (a *App) serverMod(ip string, gamePort int, workshopID string) dayz.Mod {
+	a.serverModsMu.Lock()
+	defer a.serverModsMu.Unlock()
+	for _, m := range a.serverMods[fmt.Sprintf("%s:%d", ip, gamePort)] {
+		if m.WorkshopID == workshopID {
+			return m
+		}
+	}
+	return dayz.Mod{WorkshopID: workshopID}
+}
+
+// resolveLaunchMods turns the launch mod list into folder paths, keeping the
+// server's load order. Entries are Workshop IDs or local mod names/paths; a
+// registered local copy of a Workshop mod takes precedence over the download.
+// Entries that couldn't be turned into a usable folder are returned too.
+func (a *App) resolveLaunchMods(ip string, gamePort int, mods []string) (paths, unresolved []string) {
+	locals := a.GetLocalMods()
+
+	for _, entry := range mods {
+		if workshopIDRe.MatchString(entry) {
+			if local, _ := localModFor(locals, a.serverMod(ip, gamePort, entry)); local != nil {
+				paths = append(paths, local.Path)
+				continue
+			}
+			if p := a.steam.ResolveModPath(entry); p != "" {
+				paths = append(paths, p)
+			} else {
+				fmt.Printf("[App] Workshop mod not installed: %s\n", entry)
+				unresolved = append(unresolved, entry)
+			}
+			continue
+		}
+
+		local := dayz.FindLocalMod(locals, entry)
+		if local == nil {
+			fmt.Printf("[App] Local mod not registered: %s\n", entry)
+			unresolved = append(unresolved, entry)
+			continue
+		}
+		if err := dayz.ValidateLocalMod(local.Path); err != nil {
+			fmt.Printf("[App] Local mod %s unusable: %v\n", local.Name, err)
+			unresolved = append(unresolved, fmt.Sprintf("%s (%v)", local.Name, err))
+			continue
+		}
+		paths = append(paths, local.Path)
+	}
+	return paths, unresolved
+}
@@ -26,6 +26,11 @@ func main() {
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		// Deep links opened while running are handed to this instance
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "han-launcher-8f3c2a61",
+			OnSecondInstanceLaunch: app.onSecondInstance,
+		},
 		Bind: []interface{}{
 			app,
 		},
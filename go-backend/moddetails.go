@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"dayz-launcher-go/internal/steamworks"
+	"dayz-launcher-go/internal/workshop"
+)
+
+// A cold Steam client can take a while to answer a UGC query
+const steamDetailsTimeout = 20 * time.Second
+
+// fetchModDetails feeds the Workshop cache. The Steam client is preferred
+// (authenticated, cached by Steam, reports dependencies); the public Web
+// API is the fallback when Steam is closed or the query fails.
+func (a *App) fetchModDetails(ids []string) ([]workshop.PublishedFileDetails, error) {
+	if a.steam.HasCallbacks() {
+		details, err := a.queryModDetails(ids)
+		if err == nil {
+			return details, nil
+		}
+		fmt.Printf("[App] Steam UGC query failed, using Web API: %v\n", err)
+	}
+	return workshop.FetchDetails(a.httpClient, ids)
+}
+
+func (a *App) queryModDetails(ids []string) ([]workshop.PublishedFileDetails, error) {
+	nums := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+			nums = append(nums, n)
+		}
+	}
+	if len(nums) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), steamDetailsTimeout)
+	defer cancel()
+	results, err := a.steam.QueryUGCDetails(ctx, nums)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]workshop.PublishedFileDetails, 0, len(results))
+	for _, d := range results {
+		details = append(details, ugcToDetails(d))
+	}
+	return details, nil
+}
+
+// ugcToDetails maps SteamUGCDetails_t onto the Web API shape the cache and
+// frontend already use
+func ugcToDetails(d steamworks.UGCDetails) workshop.PublishedFileDetails {
+	out := workshop.PublishedFileDetails{
+		PublishedFileId: strconv.FormatUint(d.PublishedFileID, 10),
+		Title:           d.Title,
+		FileSize:        strconv.FormatUint(d.FileSize, 10),
+		TimeUpdated:     int64(d.TimeUpdated),
+		Creator:         strconv.FormatUint(d.Owner, 10),
+		PreviewUrl:      d.PreviewURL,
+		Description:     d.Description,
+		Result:          int(d.Result),
+		Visibility:      int(d.Visibility),
+		ConsumerAppID:   int(d.ConsumerAppID),
+	}
+	if d.Banned {
+		out.Banned = 1
+	}
+	for _, t := range d.Tags {
+		out.Tags = append(out.Tags, workshop.Tag{Tag: t})
+	}
+	for _, c := range d.Children {
+		out.Children = append(out.Children, strconv.FormatUint(c, 10))
+	}
+	return out
+}
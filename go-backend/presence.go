@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"dayz-launcher-go/internal/discord"
+	"dayz-launcher-go/internal/process"
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+// Rich presence keys. "connect" is what Steam hands to the game when a
+// friend uses "Join Game"; "status" shows in the friends list.
+const (
+	presenceConnect = "connect"
+	presenceStatus  = "status"
+	presenceServer  = "server" // server name, for other launchers
+)
+
+const (
+	// The game itself; DayZ_BE.exe and steam:// hand over to it and return
+	gameExe = "DayZ_x64.exe"
+	// Time for the game to appear after launch (BattlEye, Steam updates)
+	gameStartTimeout = 3 * time.Minute
+	gamePollInterval = 5 * time.Second
+)
+
+var connectArgs = regexp.MustCompile(`-connect=([^\s"]+)\s+-port=(\d+)`)
+
+// connectString builds the launch arguments Steam passes to DayZ on join
+func connectString(ip string, port int) string {
+	return fmt.Sprintf("-connect=%s -port=%d", ip, port)
+}
+
+// parseConnectString reads ip and game port back out of a connect string
+func parseConnectString(s string) (string, int, bool) {
+	m := connectArgs.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(m[2])
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, false
+	}
+	return m[1], port, true
+}
+
+// truncatePresence keeps a value under Steam's length limit
+func truncatePresence(s string) string {
+	if max := steamworks.RichPresenceMaxValueLen - 1; len(s) > max {
+		return s[:max]
+	}
+	return s
+}
+
+// publishSessionPresence advertises the server we just launched into and
+// remembers it as the session friends get invited to
+func (a *App) publishSessionPresence(ip string, port int, serverName string) *ServerInvite {
+	session := &ServerInvite{IP: ip, GamePort: port, ServerName: serverName}
+	a.sessionMu.Lock()
+	a.session = session
+	a.sessionMu.Unlock()
+
+	if !a.steam.IsInitialized() {
+		return session
+	}
+	if serverName == "" {
+		serverName = fmt.Sprintf("%s:%d", ip, port)
+	}
+	ok := a.steam.SetRichPresence(presenceConnect, connectString(ip, port))
+	ok = a.steam.SetRichPresence(presenceStatus, truncatePresence("Playing on "+serverName)) && ok
+	ok = a.steam.SetRichPresence(presenceServer, truncatePresence(serverName)) && ok
+	if !ok {
+		fmt.Println("[App] Steam rejected part of the rich presence")
+	}
+	return session
+}
+
+// clearSessionPresence withdraws session once the game has exited, unless
+// a later launch has replaced it
+func (a *App) clearSessionPresence(session *ServerInvite) {
+	a.sessionMu.Lock()
+	current := a.session == session
+	if current {
+		a.session = nil
+	}
+	a.sessionMu.Unlock()
+	if !current {
+		return
+	}
+
+	discord.UpdatePresence("Browsing Servers", "In Launcher", "logo", "DayZ Launcher")
+	if a.steam.IsInitialized() {
+		a.steam.ClearRichPresence()
+	}
+}
+
+// watchGameSession clears session after the game has started and exited
+// again, or if it never starts. Where processes can't be listed the
+// session is kept until the next launch.
+func (a *App) watchGameSession(session *ServerInvite) {
+	deadline := time.Now().Add(gameStartTimeout)
+	started := false
+	for {
+		running, err := process.Running(gameExe)
+		switch {
+		case err != nil:
+			if !errors.Is(err, process.ErrUnsupported) {
+				fmt.Printf("[App] Can't follow the game process: %v\n", err)
+			}
+			return
+		case running:
+			if !started {
+				fmt.Println("[App] Game process started")
+			}
+			started = true
+		case started:
+			fmt.Println("[App] Game process exited")
+			a.clearSessionPresence(session)
+			return
+		case time.Now().After(deadline):
+			fmt.Println("[App] Game process never appeared, ending session")
+			a.clearSessionPresence(session)
+			return
+		}
+		time.Sleep(gamePollInterval)
+	}
+}
+
+// friendPresence reads the session keys a friend's launcher published
+func (a *App) friendPresence(steamID steamid.ID) map[string]interface{} {
+	connect := a.steam.GetFriendRichPresence(steamID.Uint64(), presenceConnect)
+	status := a.steam.GetFriendRichPresence(steamID.Uint64(), presenceStatus)
+	if connect == "" && status == "" {
+		return nil
+	}
+	p := map[string]interface{}{
+		"steamId": steamID,
+		"status":  status,
+		"server":  a.steam.GetFriendRichPresence(steamID.Uint64(), presenceServer),
+		"connect": connect,
+	}
+	if ip, port, ok := parseConnectString(connect); ok {
+		p["ip"] = ip
+		p["gamePort"] = port
+	}
+	return p
+}
+
+// GetFriendsPresence returns rich presence for friends who publish it,
+// keyed by SteamID. Friends Steam has no data for yet are asked for it;
+// their presence arrives as "friend-rich-presence" events.
+func (a *App) GetFriendsPresence() (interface{}, error) {
+	if !a.steam.IsInitialized() {
+		return map[string]interface{}{"success": false, "error": "Steam not initialized"}, nil
+	}
+	presence := make(map[string]interface{})
+	for _, f := range a.steam.GetFriends() {
+		if !f.IsOnline {
+			continue
+		}
+		if p := a.friendPresence(f.SteamID); p != nil {
+			presence[f.SteamID.String()] = p
+		} else if f.IsPlaying {
+			a.steam.RequestFriendRichPresence(f.SteamID.Uint64())
+		}
+	}
+	return map[string]interface{}{"success": true, "presence": presence}, nil
+}
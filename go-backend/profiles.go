@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"dayz-launcher-go/internal/profiles"
+	"dayz-launcher-go/internal/steamid"
+)
+
+func newProfileStore() *profiles.Store {
+	dir := ensureConfigDir()
+	if dir == "" {
+		return nil
+	}
+	return profiles.NewStore(filepath.Join(dir, "profiles"))
+}
+
+// activeSteamID is the logged in account, or profiles.DefaultID while
+// Steam isn't connected
+func (a *App) activeSteamID() steamid.ID {
+	if !a.steam.IsInitialized() {
+		return profiles.DefaultID
+	}
+	id := steamid.ID(a.steam.GetSteamID())
+	if !id.Valid() {
+		return profiles.DefaultID
+	}
+	return id
+}
+
+// syncProfile switches to the logged in account's profile when it has
+// changed, and tells the frontend. Staying on an account's profile while
+// Steam is briefly disconnected avoids flipping back to the default.
+func (a *App) syncProfile() {
+	if a.profiles == nil {
+		return
+	}
+	id := a.activeSteamID()
+
+	a.profileMu.Lock()
+	if a.profile != nil && (a.profile.SteamID == id || id == profiles.DefaultID) {
+		a.profileMu.Unlock()
+		return
+	}
+	a.profileMu.Unlock()
+
+	isNew := !a.profiles.Exists(id)
+	p, err := a.profiles.Load(id)
+	if err != nil {
+		fmt.Printf("[App] Could not load profile %s: %v\n", id, err)
+		p = &profiles.Profile{SteamID: id}
+	}
+	if name := a.steam.GetPersonaName(); name != "" && id != profiles.DefaultID {
+		p.PersonaName = name
+	}
+
+	a.profileMu.Lock()
+	a.profile = p
+	a.profileMu.Unlock()
+
+	fmt.Printf("[App] Using profile %s (%s)\n", id, p.PersonaName)
+	a.emit("profile-changed", a.profileInfo(p, isNew))
+}
+
+// profileInfo is the profile plus what the frontend needs to offer a
+// migration: a brand new account profile while older settings exist
+func (a *App) profileInfo(p *profiles.Profile, isNew bool) map[string]interface{} {
+	canMigrate := false
+	if p.SteamID != profiles.DefaultID && !p.Migrated && p.Empty() {
+		canMigrate = isNew || a.profiles.Exists(profiles.DefaultID)
+	}
+	return map[string]interface{}{
+		"success":    true,
+		"profile":    p,
+		"isNew":      isNew,
+		"canMigrate": canMigrate,
+	}
+}
+
+// currentProfile returns a copy of the active profile
+func (a *App) currentProfile() (profiles.Profile, bool) {
+	a.syncProfile()
+	a.profileMu.Lock()
+	defer a.profileMu.Unlock()
+	if a.profile == nil {
+		return profiles.Profile{}, false
+	}
+	return *a.profile, true
+}
+
+// GetProfile returns the settings of the logged in Steam account
+func (a *App) GetProfile() (interface{}, error) {
+	p, ok := a.currentProfile()
+	if !ok {
+		return map[string]interface{}{"success": false, "error": "Profiles unavailable"}, nil
+	}
+	return a.profileInfo(&p, !a.profiles.Exists(p.SteamID)), nil
+}
+
+// SaveProfile stores settings for the logged in account. The SteamID in
+// p is ignored, so a stale frontend can't write into another account.
+func (a *App) SaveProfile(p profiles.Profile) (interface{}, error) {
+	cur, ok := a.currentProfile()
+	if !ok {
+		return map[string]interface{}{"success": false, "error": "Profiles unavailable"}, nil
+	}
+	p.SteamID = cur.SteamID
+	p.Migrated = p.Migrated || cur.Migrated
+	if p.PersonaName == "" {
+		p.PersonaName = cur.PersonaName
+	}
+	if err := a.profiles.Save(&p); err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+
+	a.profileMu.Lock()
+	if a.profile != nil && a.profile.SteamID == p.SteamID {
+		a.profile = &p
+	}
+	a.profileMu.Unlock()
+	return map[string]interface{}{"success": true, "profile": p}, nil
+}
+
+// MigrateGlobalSettings copies settings from before profiles existed into
+// the logged in account's profile: legacy is what the frontend had stored
+// globally, merged with the default profile. Nothing the account already
+// has is overwritten.
+func (a *App) MigrateGlobalSettings(legacy profiles.Profile) (interface{}, error) {
+	cur, ok := a.currentProfile()
+	if !ok {
+		return map[string]interface{}{"success": false, "error": "Profiles unavailable"}, nil
+	}
+	if cur.SteamID == profiles.DefaultID {
+		return map[string]interface{}{"success": false, "error": "Log in to Steam to migrate settings"}, nil
+	}
+
+	cur.Merge(&legacy)
+	def, err := a.profiles.Load(profiles.DefaultID)
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	cur.Merge(def)
+	cur.Migrated = true
+	fmt.Printf("[App] Migrated global settings into profile %s\n", cur.SteamID)
+	return a.SaveProfile(cur)
+}
+
+// ListProfiles returns the accounts that have used this launcher
+func (a *App) ListProfiles() (interface{}, error) {
+	if a.profiles == nil {
+		return map[string]interface{}{"success": false, "error": "Profiles unavailable"}, nil
+	}
+	list, err := a.profiles.List()
+	if err != nil {
+		return map[string]interface{}{"success": false, "error": err.Error()}, nil
+	}
+	return map[string]interface{}{"success": true, "profiles": list, "active": a.activeSteamID()}, nil
+}
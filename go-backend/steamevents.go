@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"dayz-launcher-go/internal/dayz"
+	"dayz-launcher-go/internal/steamid"
+	"dayz-launcher-go/internal/steamworks"
+)
+
+// With callbacks available, an idle launcher only rescans subscribed items
+// this often to catch downloads Steam starts on its own (e.g. updates)
+const idleDownloadScan = 30 * time.Second
+
+// handleSteamCallback turns Steam callbacks into frontend events. It runs
+// on the callback loop, between RunCallbacks ticks.
+func (a *App) handleSteamCallback(event interface{}) {
+	switch ev := event.(type) {
+	case steamworks.ItemInstalled:
+		if ev.AppID != dayz.AppID {
+			return
+		}
+		id := strconv.FormatUint(ev.PublishedFileID, 10)
+		fmt.Printf("[App] Mod %s installed\n", id)
+		a.dlStats.Forget(id)
+		a.downloads.Poll()
+		a.emit("mod-installed", map[string]interface{}{"id": id})
+
+	case steamworks.DownloadItemResult:
+		if ev.AppID != dayz.AppID {
+			return
+		}
+		id := strconv.FormatUint(ev.PublishedFileID, 10)
+		success := ev.Result == 1
+		if !success {
+			fmt.Printf("[App] Download of %s failed (EResult %d)\n", id, ev.Result)
+			a.downloads.DownloadFailed(id)
+		}
+		a.emit("mod-download-result", map[string]interface{}{"id": id, "success": success, "result": ev.Result})
+
+	case steamworks.PublishedFileSubscribed:
+		if ev.AppID != dayz.AppID {
+			return
+		}
+		a.markDownloadsActive()
+		a.emit("mod-subscription-changed", map[string]interface{}{
+			"id": strconv.FormatUint(ev.PublishedFileID, 10), "subscribed": true,
+		})
+
+	case steamworks.PublishedFileUnsubscribed:
+		if ev.AppID != dayz.AppID {
+			return
+		}
+		a.emit("mod-subscription-changed", map[string]interface{}{
+			"id": strconv.FormatUint(ev.PublishedFileID, 10), "subscribed": false,
+		})
+
+	case steamworks.PersonaStateChange:
+		if ev.SteamID == a.steam.GetSteamID() && ev.ChangeFlags&steamworks.PersonaChangeName != 0 {
+			a.rememberPersonaName(a.steam.GetPersonaName())
+		}
+		if ev.ChangeFlags&steamworks.PersonaChangeAvatar != 0 {
+			a.avatarChanged(ev.SteamID)
+		}
+		// SteamIDs go out as strings, JS numbers can't hold them
+		a.emit("persona-state-change", map[string]interface{}{
+			"steamId": steamid.ID(ev.SteamID), "flags": ev.ChangeFlags,
+		})
+
+	case steamworks.AvatarImageLoaded:
+		a.avatarLoaded(ev)
+
+	case steamworks.GameRichPresenceJoinRequested:
+		a.steamJoinRequested(ev)
+
+	case steamworks.FriendRichPresenceUpdate:
+		if ev.AppID != dayz.AppID {
+			return
+		}
+		// An empty payload means the friend cleared their presence
+		p := a.friendPresence(steamid.ID(ev.SteamID))
+		if p == nil {
+			p = map[string]interface{}{"steamId": steamid.ID(ev.SteamID)}
+		}
+		a.emit("friend-rich-presence", p)
+	}
+}
+
+// markDownloadsActive makes the download loop poll at full rate again
+func (a *App) markDownloadsActive() {
+	a.pollMu.Lock()
+	a.downloadsActive = true
+	a.pollMu.Unlock()
+}
+
+// shouldPollDownloads decides whether this download tick does any work.
+// Without callbacks every tick polls, as before. With them, polling only
+// runs while something is downloading, plus a slow idle rescan.
+func (a *App) shouldPollDownloads(now time.Time) bool {
+	if !a.steam.HasCallbacks() {
+		return true
+	}
+	a.pollMu.Lock()
+	defer a.pollMu.Unlock()
+	if a.downloadsActive || a.downloads.Active() || now.Sub(a.lastDownloadScan) >= idleDownloadScan {
+		a.lastDownloadScan = now
+		return true
+	}
+	return false
+}
+
+// setDownloadsActive records whether the last poll saw any download
+func (a *App) setDownloadsActive(active bool) {
+	a.pollMu.Lock()
+	a.downloadsActive = active
+	a.pollMu.Unlock()
+}